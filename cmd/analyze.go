@@ -6,12 +6,14 @@ import (
 	"io"
 	"os"
 
-	"github.com/dwilkolek/reference-finder/cmd/runner"
+	"github.com/dwilkolek/reference-finder/runner"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	analyzeCmd.PersistentFlags().StringP("config", "i", "config.json", "Config file")
+	analyzeCmd.PersistentFlags().Bool("fail-fast", false, "Abort whole scan on first failed repository")
+	analyzeCmd.PersistentFlags().Bool("no-cache", false, "Rescan all repositories ignoring cached findings")
 	rootCmd.AddCommand(analyzeCmd)
 }
 
@@ -35,7 +37,23 @@ var analyzeCmd = &cobra.Command{
 		data, _ := io.ReadAll(jsonFile)
 		var config runner.Config
 
-		_ = json.Unmarshal([]byte(data), &config)
-		runner.Execute(config)
+		err = json.Unmarshal([]byte(data), &config)
+		if err != nil {
+			fmt.Printf("Failed to parse json from file %s: %s\n", configFile, err)
+			os.Exit(1)
+		}
+
+		if failFast, _ := cmd.Flags().GetBool("fail-fast"); failFast {
+			config.FailFast = true
+		}
+
+		if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+			config.DisableCache = true
+		}
+
+		if err := runner.Execute(config); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	checkCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	checkCmd.PersistentFlags().StringP("rules", "r", "rules.json", "Rules file")
+	checkCmd.PersistentFlags().StringP("format", "f", "text", "Output format: text or json")
+	checkCmd.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
+	checkCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from analysis")
+	checkCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+
+	rootCmd.AddCommand(checkCmd)
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks architecture rules, exits with code 1 on violations",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		rulesFile, _ := cmd.Flags().GetString("rules")
+		format, _ := cmd.Flags().GetString("format")
+		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
+
+		data, err := os.ReadFile(rulesFile)
+		if err != nil {
+			fmt.Printf("Failed to read file %s: %s\n", rulesFile, err)
+			os.Exit(1)
+		}
+		rules, err := runner.ParseRules(data)
+		if err != nil {
+			fmt.Printf("Failed to parse rules from file %s: %s\n", rulesFile, err)
+			os.Exit(1)
+		}
+
+		resources := readResources(input)
+		exclude, validTags, _ := readFilters(cmd)
+		violations, err := runner.CheckRules(resources, rules, readGrouppingFile(groupDefinitions), exclude, validTags)
+		if err != nil {
+			fmt.Printf("Invalid rules in file %s: %s\n", rulesFile, err)
+			os.Exit(1)
+		}
+
+		switch format {
+		case "text":
+			fmt.Printf("Violations: %d\n", len(violations))
+			for _, violation := range violations {
+				fmt.Print(violation)
+			}
+		case "json":
+			data, _ := json.MarshalIndent(violations, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+
+		if len(violations) > 0 {
+			os.Exit(1)
+		}
+	},
+}
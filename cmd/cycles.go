@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cyclesCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	cyclesCmd.PersistentFlags().StringP("allowlist", "a", "", "Allowed cycles. One line - one cycle, tags separated by comma")
+	cyclesCmd.PersistentFlags().StringP("format", "f", "text", "Output format: text or json")
+	cyclesCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from analysis")
+	cyclesCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+
+	rootCmd.AddCommand(cyclesCmd)
+}
+
+var cyclesCmd = &cobra.Command{
+	Use:   "cycles",
+	Short: "Detects circular dependencies, exits with code 1 when found outside allowlist",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		allowlistFile, _ := cmd.Flags().GetString("allowlist")
+		format, _ := cmd.Flags().GetString("format")
+
+		allowlist := [][]string{}
+		if len(allowlistFile) > 0 {
+			lines, err := readLines(allowlistFile)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			for _, line := range lines {
+				tags := []string{}
+				for _, tag := range strings.Split(line, ",") {
+					if tag = strings.TrimSpace(tag); len(tag) > 0 {
+						tags = append(tags, tag)
+					}
+				}
+				if len(tags) > 0 && !strings.HasPrefix(tags[0], "#") {
+					allowlist = append(allowlist, tags)
+				}
+			}
+		}
+
+		resources := readResources(input)
+		exclude, validTags, _ := readFilters(cmd)
+		cycles := runner.FindCycles(resources, exclude, validTags)
+
+		disallowed := 0
+		for _, cycle := range cycles {
+			if !cycle.Allowed(allowlist) {
+				disallowed++
+			}
+		}
+
+		switch format {
+		case "text":
+			fmt.Printf("Cycles: %d, not allowed: %d\n", len(cycles), disallowed)
+			for _, cycle := range cycles {
+				if cycle.Allowed(allowlist) {
+					fmt.Print("[allowed] ")
+				}
+				fmt.Print(cycle)
+			}
+		case "json":
+			data, _ := json.MarshalIndent(cycles, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+
+		if disallowed > 0 {
+			os.Exit(1)
+		}
+	},
+}
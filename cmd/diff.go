@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	diffCmd.PersistentFlags().StringP("format", "f", "text", "Output format: text, markdown or json")
+	diffCmd.PersistentFlags().StringP("output", "o", "", "Output file, stdout when empty")
+	diffCmd.PersistentFlags().Bool("fail-on-new-edges", false, "Exit with code 1 when new edges appeared")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff old.json new.json",
+	Short: "Compares two analyzer outputs",
+	Long:  ``,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		failOnNewEdges, _ := cmd.Flags().GetBool("fail-on-new-edges")
+
+		diff := runner.DiffOutputs(readResources(args[0]), readResources(args[1]))
+
+		var content string
+		switch format {
+		case "text":
+			content = diff.Text()
+		case "markdown":
+			content = diff.Markdown()
+		case "json":
+			data, _ := json.MarshalIndent(diff, "", "  ")
+			content = string(data) + "\n"
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+
+		writeOrPrint(output, content)
+
+		if failOnNewEdges && len(diff.AddedEdges) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// writeOrPrint saves content to file or prints it to stdout when no file is given.
+func writeOrPrint(output string, content string) {
+	if len(output) == 0 {
+		fmt.Print(content)
+		return
+	}
+	fmt.Printf("Saving to %s\n", output)
+	os.Remove(output)
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
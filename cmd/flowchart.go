@@ -6,9 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
-	"github.com/dwilkolek/reference-finder/cmd/runner"
+	"github.com/dwilkolek/reference-finder/runner"
 	"github.com/spf13/cobra"
 )
 
@@ -16,11 +17,13 @@ func init() {
 	flowchart.PersistentFlags().StringP("input", "i", "output.json", "Input file")
 	flowchart.PersistentFlags().StringP("output", "o", "flowchart.txt", "Output file")
 	flowchart.PersistentFlags().StringP("resource", "r", "", "Chart for single resource")
+	flowchart.PersistentFlags().StringP("direction", "d", runner.DirectionBoth, "With resource draw only its upstream, downstream or both")
 	flowchart.PersistentFlags().StringP("exclude", "e", "", "Exclude from chart")
 	flowchart.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
 	flowchart.PersistentFlags().Bool("include-orphans", false, "Include orphan center")
 	flowchart.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
 	flowchart.PersistentFlags().StringP("translation", "t", "", "Mapping tags to display names. One line - one translation. Separated by ;.")
+	flowchart.PersistentFlags().StringP("format", "f", "mermaid", "Output format: mermaid, dot, plantuml or c4")
 
 	rootCmd.AddCommand(flowchart)
 }
@@ -33,60 +36,94 @@ var flowchart = &cobra.Command{
 		input, _ := cmd.Flags().GetString("input")
 		output, _ := cmd.Flags().GetString("output")
 		tag, _ := cmd.Flags().GetString("resource")
-
-		jsonFile, err := os.Open(input)
-		if err != nil {
-			fmt.Printf("Failed to read file %s: %s\n", input, err)
+		direction, _ := cmd.Flags().GetString("direction")
+		if !slices.Contains([]string{runner.DirectionBoth, runner.DirectionUpstream, runner.DirectionDownstream}, direction) {
+			fmt.Printf("Unknown direction %s\n", direction)
 			os.Exit(1)
 		}
-		defer jsonFile.Close()
-		data, _ := io.ReadAll(jsonFile)
-		var resources []runner.Resource
-
-		err = json.Unmarshal([]byte(data), &resources)
 
-		if err != nil {
-			fmt.Printf("Failed to parse json from file %s: %s\n", input, err)
-			os.Exit(1)
-		}
+		resources := readResources(input)
 
 		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
 
 		orphanCenter, _ := cmd.Flags().GetBool("include-orphans")
 
-		validTagsFile, _ := cmd.Flags().GetString("valid-tags")
-		validTags := []string{}
-		if len(validTagsFile) > 0 {
-			validTags, _ = readLines(validTagsFile)
-		}
-
-		excludeFile, _ := cmd.Flags().GetString("exclude")
-		exclude := []string{}
-		if len(excludeFile) > 0 {
-			exclude, _ = readLines(excludeFile)
-		}
-
-		translationMappingFile, _ := cmd.Flags().GetString("translation")
-		translationMapping := map[string]string{}
-		if len(translationMappingFile) > 0 {
-			translations, _ := readLines(translationMappingFile)
-			for _, t := range translations {
-				pair := strings.Split(t, ";")
-				translationMapping[pair[0]] = pair[1]
-			}
+		exclude, validTags, translationMapping := readFilters(cmd)
+
+		format, _ := cmd.Flags().GetString("format")
+		var flowchart string
+		switch format {
+		case "mermaid":
+			flowchart = runner.GenerateFlowchart(resources, tag, direction, exclude, readGrouppingFile(groupDefinitions), orphanCenter, validTags, translationMapping)
+		case "dot":
+			flowchart = runner.GenerateDot(resources, tag, direction, exclude, readGrouppingFile(groupDefinitions), orphanCenter, validTags, translationMapping)
+		case "plantuml", "c4":
+			flowchart = runner.GeneratePlantUML(resources, tag, direction, exclude, readGrouppingFile(groupDefinitions), orphanCenter, validTags, translationMapping, format == "c4")
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
 		}
 
-		flowchart := runner.GenerateFlowchart(resources, tag, exclude, readGrouppingFile(groupDefinitions), orphanCenter, validTags, translationMapping)
-
 		fmt.Printf("Saving to %s\n", output)
 		os.Remove(output)
-		err = os.WriteFile(output, []byte(flowchart), 0644)
+		err := os.WriteFile(output, []byte(flowchart), 0644)
 		if err != nil {
 			fmt.Println(err)
 		}
 	},
 }
 
+// readResources reads analyzer output, accepting both versioned schema and legacy list of resources.
+func readResources(file string) []runner.Resource {
+	resources, err := parseResourcesFile(file)
+	if err != nil {
+		fmt.Printf("Failed to read resources from file %s: %s\n", file, err)
+		os.Exit(1)
+	}
+	return resources
+}
+
+func parseResourcesFile(file string) ([]runner.Resource, error) {
+	jsonFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+	data, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	return runner.ParseOutput(data)
+}
+
+// readFilters reads files given by exclude, valid-tags and translation flags.
+func readFilters(cmd *cobra.Command) ([]string, []string, map[string]string) {
+	validTagsFile, _ := cmd.Flags().GetString("valid-tags")
+	validTags := []string{}
+	if len(validTagsFile) > 0 {
+		validTags, _ = readLines(validTagsFile)
+	}
+
+	excludeFile, _ := cmd.Flags().GetString("exclude")
+	exclude := []string{}
+	if len(excludeFile) > 0 {
+		exclude, _ = readLines(excludeFile)
+	}
+
+	translationMappingFile, _ := cmd.Flags().GetString("translation")
+	translationMapping := map[string]string{}
+	if len(translationMappingFile) > 0 {
+		translations, _ := readLines(translationMappingFile)
+		for _, t := range translations {
+			pair := strings.Split(t, ";")
+			if len(pair) > 1 {
+				translationMapping[pair[0]] = pair[1]
+			}
+		}
+	}
+	return exclude, validTags, translationMapping
+}
+
 func readGrouppingFile(file string) map[string][]string {
 	if len(file) == 0 {
 		return map[string][]string{}
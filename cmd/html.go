@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	htmlCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	htmlCmd.PersistentFlags().StringP("output", "o", "graph.html", "Output file")
+	htmlCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from graph")
+	htmlCmd.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
+	htmlCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+	htmlCmd.PersistentFlags().StringP("translation", "t", "", "Mapping tags to display names. One line - one translation. Separated by ;.")
+
+	rootCmd.AddCommand(htmlCmd)
+}
+
+var htmlCmd = &cobra.Command{
+	Use:   "html",
+	Short: "Generates offline interactive graph viewer",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+
+		resources := readResources(input)
+
+		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
+		exclude, validTags, translationMapping := readFilters(cmd)
+
+		page := runner.GenerateHtml(resources, exclude, readGrouppingFile(groupDefinitions), validTags, translationMapping)
+
+		fmt.Printf("Saving to %s\n", output)
+		os.Remove(output)
+		err := os.WriteFile(output, []byte(page), 0644)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	impactCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	impactCmd.PersistentFlags().StringSliceP("resource", "r", []string{}, "Resources to analyse, repeat or separate by comma")
+	impactCmd.PersistentFlags().StringP("direction", "d", runner.DirectionUpstream, "Walk to resources using given ones (upstream), used by them (downstream) or both")
+	impactCmd.PersistentFlags().Int("depth", 0, "Maximum distance, 0 for no limit")
+	impactCmd.PersistentFlags().StringP("format", "f", "text", "Output format: text or json")
+	impactCmd.PersistentFlags().StringP("mermaid", "m", "", "Save affected subgraph as mermaid flowchart to file")
+	impactCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from analysis")
+	impactCmd.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
+	impactCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+	impactCmd.PersistentFlags().StringP("translation", "t", "", "Mapping tags to display names. One line - one translation. Separated by ;.")
+
+	rootCmd.AddCommand(impactCmd)
+}
+
+var impactCmd = &cobra.Command{
+	Use:   "impact",
+	Short: "Lists resources transitively affected by given resources",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		tags, _ := cmd.Flags().GetStringSlice("resource")
+		direction, _ := cmd.Flags().GetString("direction")
+		depth, _ := cmd.Flags().GetInt("depth")
+		format, _ := cmd.Flags().GetString("format")
+		mermaid, _ := cmd.Flags().GetString("mermaid")
+
+		if len(tags) == 0 {
+			fmt.Println("At least one resource required")
+			os.Exit(1)
+		}
+		if !slices.Contains([]string{runner.DirectionBoth, runner.DirectionUpstream, runner.DirectionDownstream}, direction) {
+			fmt.Printf("Unknown direction %s\n", direction)
+			os.Exit(1)
+		}
+
+		resources := readResources(input)
+		exclude, validTags, translationMapping := readFilters(cmd)
+
+		impacts := runner.AnalyzeImpact(resources, tags, direction, depth, exclude, validTags)
+
+		switch format {
+		case "text":
+			separator := map[string]string{runner.DirectionUpstream: " <- ", runner.DirectionDownstream: " -> ", runner.DirectionBoth: " - "}[direction]
+			fmt.Printf("Affected resources: %d\n", len(impacts))
+			for _, impact := range impacts {
+				fmt.Printf("%d\t%s\t%s\n", impact.Distance, impact.Tag, strings.Join(impact.Path, separator))
+			}
+		case "json":
+			data, _ := json.MarshalIndent(impacts, "", "  ")
+			fmt.Println(string(data))
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+
+		if len(mermaid) > 0 {
+			groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
+			subgraph := runner.ImpactGraph(resources, impacts, direction)
+			flowchart := runner.GenerateFlowchart(subgraph, "", runner.DirectionBoth, exclude, readGrouppingFile(groupDefinitions), false, validTags, translationMapping)
+
+			fmt.Printf("Saving to %s\n", mermaid)
+			os.Remove(mermaid)
+			if err := os.WriteFile(mermaid, []byte(flowchart), 0644); err != nil {
+				fmt.Println(err)
+			}
+		}
+	},
+}
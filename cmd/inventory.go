@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	inventoryCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	inventoryCmd.PersistentFlags().StringP("output", "o", "", "Output file, stdout when empty")
+	inventoryCmd.PersistentFlags().StringP("format", "f", "markdown", "Output format: markdown, csv or json")
+	inventoryCmd.PersistentFlags().Bool("matrix", false, "Render resource × technology table")
+	inventoryCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from inventory")
+	inventoryCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+
+	rootCmd.AddCommand(inventoryCmd)
+}
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Lists resources using every detected technology and version",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		matrix, _ := cmd.Flags().GetBool("matrix")
+
+		resources := readResources(input)
+		exclude, validTags, _ := readFilters(cmd)
+		inventory := runner.BuildInventory(resources, exclude, validTags)
+
+		var content string
+		switch format {
+		case "markdown":
+			content = inventory.Markdown(matrix)
+		case "csv":
+			content = inventory.CSV(matrix)
+		case "json":
+			data, _ := json.MarshalIndent(inventory, "", "  ")
+			content = string(data) + "\n"
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+		writeOrPrint(output, content)
+	},
+}
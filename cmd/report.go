@@ -1,14 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"slices"
-	"strings"
 
-	"github.com/dwilkolek/reference-finder/cmd/runner"
 	"github.com/spf13/cobra"
 )
 
@@ -31,46 +27,12 @@ var reportCmd = &cobra.Command{
 		input, _ := cmd.Flags().GetString("input")
 		output, _ := cmd.Flags().GetString("output")
 
-		jsonFile, err := os.Open(input)
-		if err != nil {
-			fmt.Printf("Failed to read file %s: %s\n", input, err)
-			os.Exit(1)
-		}
-		defer jsonFile.Close()
-		data, _ := io.ReadAll(jsonFile)
-		var resources []runner.Resource
-
-		err = json.Unmarshal([]byte(data), &resources)
-
-		if err != nil {
-			fmt.Printf("Failed to parse json from file %s: %s\n", input, err)
-			os.Exit(1)
-		}
+		resources := readResources(input)
 
 		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
 		groups := readGrouppingFile(groupDefinitions)
 
-		validTagsFile, _ := cmd.Flags().GetString("valid-tags")
-		validTags := []string{}
-		if len(validTagsFile) > 0 {
-			validTags, _ = readLines(validTagsFile)
-		}
-
-		excludeFile, _ := cmd.Flags().GetString("exclude")
-		exclude := []string{}
-		if len(excludeFile) > 0 {
-			exclude, _ = readLines(excludeFile)
-		}
-
-		translationMappingFile, _ := cmd.Flags().GetString("translation")
-		translationMapping := map[string]string{}
-		if len(translationMappingFile) > 0 {
-			translations, _ := readLines(translationMappingFile)
-			for _, t := range translations {
-				pair := strings.Split(t, ";")
-				translationMapping[pair[0]] = pair[1]
-			}
-		}
+		exclude, validTags, translationMapping := readFilters(cmd)
 
 		reportMd := ""
 		reportEntires := map[string][]string{}
@@ -129,6 +91,23 @@ var reportCmd = &cobra.Command{
 
 				reportEntry += depsPart
 			}
+
+			usedByPart := "### Used by:\n\n"
+			hasUsedBy := false
+			for _, user := range resource.UsedBy {
+				if slices.Contains(exclude, user) {
+					continue
+				}
+				if len(validTags) > 0 && !slices.Contains(validTags, user) {
+					continue
+				}
+				hasUsedBy = true
+				usedByPart += fmt.Sprintf("- %s\n", user)
+			}
+			usedByPart += "\n\n"
+			if hasUsedBy {
+				reportEntry += usedByPart
+			}
 			entryKey := fmt.Sprintf("%04d", 1000-priority)
 			reportEntires[entryKey] = append(reportEntires[entryKey], reportEntry)
 		}
@@ -147,7 +126,7 @@ var reportCmd = &cobra.Command{
 
 		fmt.Printf("Saving to %s\n", output)
 		os.Remove(output)
-		err = os.WriteFile(output, []byte(reportMd), 0644)
+		err := os.WriteFile(output, []byte(reportMd), 0644)
 		if err != nil {
 			fmt.Println(err)
 		}
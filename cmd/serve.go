@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	serveCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	serveCmd.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
+	serveCmd.PersistentFlags().StringP("address", "a", "localhost:8080", "Address to listen on")
+	serveCmd.PersistentFlags().Duration("reload-interval", 2*time.Second, "How often input file is checked for changes")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves output over local HTTP API",
+	Long: `Endpoints:
+  GET /resources                  all resources
+  GET /resources/{tag}            resource with outgoing and incoming edges
+  GET /edges/{source}/{target}    references behind edge
+  GET /software                   resources using every detected software
+  GET /groups                     group membership
+
+Tags in path are escaped, e.g. team%2Fservice for team/service.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		address, _ := cmd.Flags().GetString("address")
+		interval, _ := cmd.Flags().GetDuration("reload-interval")
+		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
+
+		modified := modTime(input)
+		api := runner.NewApi(readResources(input), readGrouppingFile(groupDefinitions))
+
+		go func() {
+			for range time.Tick(interval) {
+				current := modTime(input)
+				if current.Equal(modified) {
+					continue
+				}
+				resources, err := parseResourcesFile(input)
+				if err != nil {
+					fmt.Printf("Failed to reload %s, serving previous data: %s\n", input, err)
+					continue
+				}
+				modified = current
+				fmt.Printf("Reloaded %s\n", input)
+				api.Update(resources)
+			}
+		}()
+
+		fmt.Printf("Serving %s on http://%s\n", input, address)
+		if err := http.ListenAndServe(address, api); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func modTime(file string) time.Time {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
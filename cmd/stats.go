@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dwilkolek/reference-finder/runner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	statsCmd.PersistentFlags().StringP("input", "i", "output.json", "Input file")
+	statsCmd.PersistentFlags().StringP("output", "o", "", "Output file, stdout when empty")
+	statsCmd.PersistentFlags().StringP("format", "f", "markdown", "Output format: markdown, csv or json")
+	statsCmd.PersistentFlags().StringP("sort", "s", runner.SortName, "Sort by: name, fan-in, fan-out, betweenness or depth")
+	statsCmd.PersistentFlags().Bool("groups", false, "Export group table instead of resource table as csv")
+	statsCmd.PersistentFlags().StringP("exclude", "e", "", "Exclude from analysis")
+	statsCmd.PersistentFlags().StringP("group-definitions", "g", "", "Group definitions specification")
+	statsCmd.PersistentFlags().StringP("valid-tags", "v", "", "List of valid tags")
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Computes graph metrics per resource and group",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		sort, _ := cmd.Flags().GetString("sort")
+		groupTable, _ := cmd.Flags().GetBool("groups")
+		groupDefinitions, _ := cmd.Flags().GetString("group-definitions")
+
+		resources := readResources(input)
+		exclude, validTags, _ := readFilters(cmd)
+
+		stats := runner.ComputeStats(resources, readGrouppingFile(groupDefinitions), exclude, validTags)
+		if err := stats.Sort(sort); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		var content string
+		switch format {
+		case "markdown":
+			content = stats.Markdown()
+		case "csv":
+			content = stats.CSV(groupTable)
+		case "json":
+			data, _ := json.MarshalIndent(stats, "", "  ")
+			content = string(data) + "\n"
+		default:
+			fmt.Printf("Unknown format %s\n", format)
+			os.Exit(1)
+		}
+		writeOrPrint(output, content)
+	},
+}
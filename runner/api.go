@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Api serves analyzer output over HTTP, data can be replaced while serving.
+type Api struct {
+	lock      sync.RWMutex
+	resources []Resource
+	byTag     map[string]Resource
+	incoming  map[string]map[string][]Reference
+	groups    map[string][]string
+}
+
+type apiEdge struct {
+	Tag        string   `json:"tag"`
+	Kinds      []string `json:"kinds"`
+	References int      `json:"references"`
+}
+
+type apiResource struct {
+	Tag      string    `json:"tag"`
+	Commit   string    `json:"commit,omitempty"`
+	Software []string  `json:"software"`
+	Groups   []string  `json:"groups"`
+	Outgoing []apiEdge `json:"outgoing"`
+	Incoming []apiEdge `json:"incoming"`
+}
+
+type apiEdgeReferences struct {
+	Source     string      `json:"source"`
+	Target     string      `json:"target"`
+	References []Reference `json:"references"`
+}
+
+type apiSoftware struct {
+	Software  string   `json:"software"`
+	Resources []string `json:"resources"`
+}
+
+func NewApi(resources []Resource, groups map[string][]string) *Api {
+	api := &Api{groups: groups}
+	api.Update(resources)
+	return api
+}
+
+// Update replaces served resources.
+func (api *Api) Update(resources []Resource) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	api.resources = resources
+	api.byTag = resourcesByTag(resources)
+	api.incoming = IncomingReferences(resources)
+}
+
+// ServeHTTP routes:
+//
+//	GET /resources                  all resources
+//	GET /resources/{tag}            resource with outgoing and incoming edges
+//	GET /edges/{source}/{target}    references behind edge
+//	GET /software                   resources using every detected software
+//	GET /groups                     group membership
+//
+// Tags are path escaped, so tag containing `/` is requested as %2F.
+func (api *Api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJson(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	api.lock.RLock()
+	defer api.lock.RUnlock()
+
+	path := strings.Trim(r.URL.EscapedPath(), "/")
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			writeJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		parts[i] = unescaped
+	}
+	switch {
+	case path == "resources":
+		writeJson(w, http.StatusOK, api.resources)
+	case len(parts) == 2 && parts[0] == "resources":
+		api.resource(w, parts[1])
+	case len(parts) == 3 && parts[0] == "edges":
+		api.edge(w, parts[1], parts[2])
+	case path == "software":
+		api.software(w)
+	case path == "groups":
+		writeJson(w, http.StatusOK, api.groups)
+	default:
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+func (api *Api) resource(w http.ResponseWriter, tag string) {
+	resource, ok := api.byTag[tag]
+	if !ok && len(api.incoming[tag]) == 0 {
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "unknown resource " + tag})
+		return
+	}
+
+	result := apiResource{
+		Tag:      tag,
+		Commit:   resource.Commit,
+		Software: resource.Software,
+		Groups:   []string{},
+		Outgoing: []apiEdge{},
+		Incoming: []apiEdge{},
+	}
+	if result.Software == nil {
+		result.Software = []string{}
+	}
+	for _, group := range sortedKeys(api.groups) {
+		if slices.Contains(api.groups[group], tag) {
+			result.Groups = append(result.Groups, group)
+		}
+	}
+	for _, target := range sortedKeys(resource.References) {
+		result.Outgoing = append(result.Outgoing, apiEdge{target, kindsOf(resource.References[target]), len(resource.References[target])})
+	}
+	for _, source := range sortedKeys(api.incoming[tag]) {
+		refs := api.incoming[tag][source]
+		result.Incoming = append(result.Incoming, apiEdge{source, kindsOf(refs), len(refs)})
+	}
+	writeJson(w, http.StatusOK, result)
+}
+
+func (api *Api) edge(w http.ResponseWriter, source string, target string) {
+	refs, ok := api.byTag[source].References[target]
+	if !ok {
+		writeJson(w, http.StatusNotFound, map[string]string{"error": "no edge " + source + " -> " + target})
+		return
+	}
+	writeJson(w, http.StatusOK, apiEdgeReferences{source, target, refs})
+}
+
+func (api *Api) software(w http.ResponseWriter) {
+	users := map[string][]string{}
+	for _, resource := range api.resources {
+		for _, software := range resource.Software {
+			users[software] = append(users[software], resource.Tag)
+		}
+	}
+	result := []apiSoftware{}
+	for _, software := range sortedKeys(users) {
+		tags := users[software]
+		slices.Sort(tags)
+		result = append(result, apiSoftware{software, tags})
+	}
+	writeJson(w, http.StatusOK, result)
+}
+
+func kindsOf(refs []Reference) []string {
+	kinds := []string{}
+	for _, ref := range refs {
+		if len(ref.Kind) > 0 && !slices.Contains(kinds, ref.Kind) {
+			kinds = append(kinds, ref.Kind)
+		}
+	}
+	return kinds
+}
+
+func writeJson(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApiRoutes(t *testing.T) {
+	api := NewApi([]Resource{
+		{Tag: "team/web", References: map[string][]Reference{"api": {{Path: "main.go", Kind: "http"}}}},
+		{Tag: "api"},
+	}, map[string][]string{"frontend": {"team/web"}})
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		tag    string
+	}{
+		{"/resources/team%2Fweb", http.StatusOK, "team/web"},
+		{"/resources/api", http.StatusOK, "api"},
+		{"/resources/team/web", http.StatusNotFound, ""},
+		{"/resources/missing", http.StatusNotFound, ""},
+		{"/edges/team%2Fweb/api", http.StatusOK, ""},
+		{"/edges/api/team%2Fweb", http.StatusNotFound, ""},
+		{"/resources", http.StatusOK, ""},
+		{"/unknown", http.StatusNotFound, ""},
+	} {
+		recorder := httptest.NewRecorder()
+		api.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if recorder.Code != tc.status {
+			t.Errorf("GET %s = %d, want %d", tc.path, recorder.Code, tc.status)
+			continue
+		}
+		if len(tc.tag) > 0 {
+			var resource apiResource
+			if err := json.Unmarshal(recorder.Body.Bytes(), &resource); err != nil || resource.Tag != tc.tag {
+				t.Errorf("GET %s returned %s, want resource %s", tc.path, recorder.Body, tc.tag)
+			}
+		}
+	}
+}
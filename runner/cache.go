@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// cacheVersion invalidates all cached findings when scanning logic changes.
+const cacheVersion = 10
+
+// cachedRoot holds per file findings of directory producing single resource, Dir is empty for whole repository.
+type cachedRoot struct {
+	Tag     string                  `json:"tag"`
+	Dir     string                  `json:"dir"`
+	Files   map[string]FileFindings `json:"files"`
+	Skipped skippedPaths            `json:"skipped"`
+}
+
+// cacheEntry holds findings of repository at given commit with given config.
+type cacheEntry struct {
+	Commit     string       `json:"commit"`
+	ConfigHash string       `json:"configHash"`
+	Roots      []cachedRoot `json:"roots"`
+}
+
+func (entry cacheEntry) resources() []Resource {
+	resources := []Resource{}
+	for _, root := range entry.Roots {
+		resources = append(resources, resourceFromFiles(root.Tag, entry.Commit, root.Files))
+	}
+	return resources
+}
+
+// skipped returns number of skipped files of all roots by reason.
+func (entry cacheEntry) skipped() map[string]int {
+	counts := map[string]int{}
+	for _, root := range entry.Roots {
+		for reason, count := range root.Skipped.counts() {
+			counts[reason] += count
+		}
+	}
+	return counts
+}
+
+func (entry cacheEntry) root(dir string) (cachedRoot, bool) {
+	for _, root := range entry.Roots {
+		if root.Dir == dir {
+			return root, true
+		}
+	}
+	return cachedRoot{}, false
+}
+
+func cacheFile(repo Repository, executionConfig ExecutionConfig) string {
+	return filepath.Join(executionConfig.CacheDir, repo.Name+".json")
+}
+
+// configHash covers every setting changing findings of repository.
+func configHash(repo Repository, executionConfig ExecutionConfig) string {
+	data, _ := json.Marshal(struct {
+		Version     int               `json:"version"`
+		Patterns    []Pattern         `json:"patterns"`
+		Aliases     map[string]string `json:"aliases"`
+		ValidNames  []string          `json:"validNames"`
+		RootLike    bool              `json:"rootLike"`
+		Include     []string          `json:"include"`
+		Exclude     []string          `json:"exclude"`
+		MaxFileSize int64             `json:"maxFileSize"`
+		Detectors   []string          `json:"detectors"`
+		Configured  []DetectorConfig  `json:"configured"`
+		Images      map[string]string `json:"images"`
+	}{
+		Version:     cacheVersion,
+		Patterns:    executionConfig.Patterns,
+		Aliases:     executionConfig.Aliases,
+		ValidNames:  executionConfig.ValidNames,
+		RootLike:    slices.Contains(executionConfig.RootLike, repo.Name),
+		Include:     executionConfig.Include,
+		Exclude:     executionConfig.Exclude,
+		MaxFileSize: executionConfig.MaxFileSize,
+		Detectors:   detectorNames(executionConfig.Detectors),
+		Configured:  executionConfig.Config.Detectors,
+		Images:      executionConfig.Images,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheable reports whether findings of checkout are fully described by its commit.
+func cacheable(ctx context.Context, source checkout, executionConfig ExecutionConfig) bool {
+	if executionConfig.DisableCache || len(executionConfig.CacheDir) == 0 || len(source.commit) == 0 {
+		return false
+	}
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
+	cmd.Dir = source.path
+	out, err := cmd.Output()
+	return err == nil && len(strings.TrimSpace(string(out))) == 0
+}
+
+// readCache returns entry of repository scanned with current config, possibly at other commit.
+func readCache(repo Repository, executionConfig ExecutionConfig) (cacheEntry, bool) {
+	data, err := os.ReadFile(cacheFile(repo, executionConfig))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	if entry.ConfigHash != configHash(repo, executionConfig) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func writeCache(repo Repository, entry cacheEntry, executionConfig ExecutionConfig) error {
+	entry.ConfigHash = configHash(repo, executionConfig)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file := cacheFile(repo, executionConfig)
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+func detectorNames(detectors []Detector) []string {
+	names := []string{}
+	for _, detector := range detectors {
+		names = append(names, detector.Name())
+	}
+	return names
+}
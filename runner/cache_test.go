@@ -0,0 +1,163 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func testExecutionConfig(t *testing.T, config Config, repositories ...Repository) ExecutionConfig {
+	t.Helper()
+	if len(config.Patterns) == 0 {
+		config.Patterns = []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}}
+	}
+	executionConfig, err := executionConfig(config, repositories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return executionConfig
+}
+
+func TestConfigHash(t *testing.T) {
+	repo := Repository{Name: "repo"}
+	base := configHash(repo, testExecutionConfig(t, Config{}, repo))
+	if again := configHash(repo, testExecutionConfig(t, Config{}, repo)); again != base {
+		t.Error("same config gives different hash")
+	}
+
+	for name, config := range map[string]Config{
+		"patterns": {Patterns: []Pattern{{Regexp: regexp.MustCompile(`http://([a-z]+)`)}}},
+		"aliases":  {Aliases: map[string]string{"old": "new"}},
+		"rootlike": {RootLike: []string{"repo"}},
+		"exclude":  {Exclude: []string{"vendor"}},
+		"size":     {MaxFileSize: 10},
+		"images":   {Images: map[string]string{"node": "Node"}},
+		"detectors": {Detectors: []DetectorConfig{
+			{Name: "make", Files: []string{"Makefile"}, Regexp: regexp.MustCompile(`GO=(\S+)`)},
+		}},
+	} {
+		if hash := configHash(repo, testExecutionConfig(t, config, repo)); hash == base {
+			t.Errorf("changing %s keeps hash", name)
+		}
+	}
+	if hash := configHash(repo, testExecutionConfig(t, Config{RootLike: []string{"other"}}, repo)); hash != base {
+		t.Error("rootlike of other repository changes hash")
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	repo := Repository{Name: "repo"}
+	executionConfig := testExecutionConfig(t, Config{}, repo)
+	executionConfig.CacheDir = filepath.Join(t.TempDir(), "cache")
+
+	if _, ok := readCache(repo, executionConfig); ok {
+		t.Error("missing cache read")
+	}
+	entry := cacheEntry{Commit: "abc", Roots: []cachedRoot{{
+		Tag:     "repo",
+		Files:   map[string]FileFindings{"main.go": {Software: []string{"Go 1.21"}}},
+		Skipped: skippedPaths{Files: map[string]string{"a.bin": SkipBinary}},
+	}}}
+	if err := writeCache(repo, entry, executionConfig); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := readCache(repo, executionConfig)
+	entry.ConfigHash = configHash(repo, executionConfig)
+	if !ok || !reflect.DeepEqual(got, entry) {
+		t.Errorf("got %+v, %v, want %+v", got, ok, entry)
+	}
+
+	changed := testExecutionConfig(t, Config{Exclude: []string{"vendor"}}, repo)
+	changed.CacheDir = executionConfig.CacheDir
+	if _, ok := readCache(repo, changed); ok {
+		t.Error("cache written with other config read")
+	}
+
+	os.WriteFile(cacheFile(repo, executionConfig), []byte("{"), 0o644)
+	if _, ok := readCache(repo, executionConfig); ok {
+		t.Error("corrupted cache read")
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	writeFiles(t, repo, map[string]string{"main.go": ""})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+	source := checkout{path: repo, commit: resolveCommit(repo)}
+
+	executionConfig := testExecutionConfig(t, Config{})
+	executionConfig.CacheDir = t.TempDir()
+	if !cacheable(context.Background(), source, executionConfig) {
+		t.Error("clean checkout not cacheable")
+	}
+
+	disabled := executionConfig
+	disabled.DisableCache = true
+	withoutDir := executionConfig
+	withoutDir.CacheDir = ""
+	for name, tc := range map[string]struct {
+		source          checkout
+		executionConfig ExecutionConfig
+	}{
+		"disabled":       {source, disabled},
+		"no cache dir":   {source, withoutDir},
+		"no commit":      {checkout{path: repo}, executionConfig},
+		"not repository": {checkout{path: t.TempDir(), commit: source.commit}, executionConfig},
+	} {
+		if cacheable(context.Background(), tc.source, tc.executionConfig) {
+			t.Errorf("%s: cacheable", name)
+		}
+	}
+
+	writeFiles(t, repo, map[string]string{"main.go": "changed"})
+	if cacheable(context.Background(), source, executionConfig) {
+		t.Error("dirty checkout cacheable")
+	}
+}
+
+func TestScanReusesCache(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	writeFiles(t, repo, map[string]string{"main.go": "// https://api.service"})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+
+	scan := func(config Config) (string, []Resource) {
+		t.Helper()
+		config.ExtendedSearch = true
+		config.Patterns = []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}}
+		scanner := NewScanner(config)
+		scanner.CacheDir = filepath.Join(root, "cache")
+		mode := ""
+		scanner.Progress = func(event Event) {
+			if event.Type == EventProcessed {
+				mode = event.Mode
+			}
+		}
+		result, err := scanner.Scan(context.Background(), []Repository{{Name: "repo", Type: SourceLocal, Path: repo}})
+		if err != nil || len(result.Failures) > 0 {
+			t.Fatal(err, result.Failures)
+		}
+		return mode, result.Resources
+	}
+
+	mode, scanned := scan(Config{})
+	if mode != ModeScanned || len(scanned) != 1 || len(scanned[0].References["api"]) != 1 {
+		t.Fatalf("got %s %+v", mode, scanned)
+	}
+	if mode, cached := scan(Config{}); mode != ModeCached || !reflect.DeepEqual(cached, scanned) {
+		t.Errorf("got %s %+v, want cached %+v", mode, cached, scanned)
+	}
+	if mode, _ := scan(Config{Exclude: []string{"*.go"}}); mode != ModeScanned {
+		t.Errorf("changed config got %s, want %s", mode, ModeScanned)
+	}
+	if mode, _ := scan(Config{DisableCache: true}); mode != ModeScanned {
+		t.Errorf("disabled cache got %s, want %s", mode, ModeScanned)
+	}
+}
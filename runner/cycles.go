@@ -0,0 +1,198 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CycleEdge is edge inside strongly connected component with references forming it.
+type CycleEdge struct {
+	Edge
+	References []Reference `json:"references"`
+}
+
+// maxCyclesPerComponent limits enumeration of elementary cycles, their number grows exponentially.
+const maxCyclesPerComponent = 100
+
+// Cycle is strongly connected component of dependency graph with elementary cycles found inside it.
+type Cycle struct {
+	Tags  []string    `json:"tags"`
+	Paths [][]string  `json:"paths"`
+	Edges []CycleEdge `json:"edges"`
+}
+
+// Allowed reports whether all tags of cycle are listed in one of allowlist entries.
+func (c Cycle) Allowed(allowlist [][]string) bool {
+	for _, allowed := range allowlist {
+		if !slices.ContainsFunc(c.Tags, func(tag string) bool { return !slices.Contains(allowed, tag) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Cycle) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s\n", strings.Join(c.Tags, ", ")))
+	sb.WriteString("\tCycles:\n")
+	for _, path := range c.Paths {
+		sb.WriteString(fmt.Sprintf("\t\t%s\n", strings.Join(path, " ---> ")))
+	}
+	if len(c.Paths) == maxCyclesPerComponent {
+		sb.WriteString("\t\t...\n")
+	}
+	sb.WriteString("\tEdges:\n")
+	for _, edge := range c.Edges {
+		sb.WriteString(fmt.Sprintf("\t\t%s\n", edge.Edge))
+		for _, ref := range edge.References {
+			sb.WriteString(fmt.Sprintf("\t\t\t%s\n", ref))
+		}
+	}
+	return sb.String()
+}
+
+// FindCycles returns strongly connected components having more than one resource or referencing itself.
+func FindCycles(resources []Resource, exclude []string, validTags []string) []Cycle {
+	neighbours := adjacency(resources, DirectionDownstream, exclude, validTags)
+	byTag := resourcesByTag(resources)
+
+	cycles := []Cycle{}
+	for _, component := range stronglyConnected(neighbours) {
+		slices.Sort(component)
+		first := component[0]
+		if len(component) == 1 && !slices.Contains(neighbours[first], first) {
+			continue
+		}
+
+		cycle := Cycle{Tags: component, Paths: elementaryCycles(component, neighbours)}
+		for _, source := range component {
+			for _, target := range neighbours[source] {
+				if slices.Contains(component, target) {
+					cycle.Edges = append(cycle.Edges, CycleEdge{
+						Edge:       Edge{Source: source, Target: target},
+						References: byTag[source].References[target],
+					})
+				}
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+	slices.SortFunc(cycles, func(a, b Cycle) int {
+		return strings.Compare(a.Tags[0], b.Tags[0])
+	})
+	return cycles
+}
+
+// stronglyConnected is Tarjan's algorithm, components come out in reverse topological order.
+func stronglyConnected(neighbours map[string][]string) [][]string {
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	components := [][]string{}
+
+	var connect func(tag string)
+	connect = func(tag string) {
+		index[tag] = len(index)
+		lowlink[tag] = index[tag]
+		stack = append(stack, tag)
+		onStack[tag] = true
+
+		for _, next := range neighbours[tag] {
+			if _, visited := index[next]; !visited {
+				connect(next)
+				lowlink[tag] = min(lowlink[tag], lowlink[next])
+			} else if onStack[next] {
+				lowlink[tag] = min(lowlink[tag], index[next])
+			}
+		}
+
+		if lowlink[tag] == index[tag] {
+			component := []string{}
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == tag {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, tag := range sortedKeys(neighbours) {
+		if _, visited := index[tag]; !visited {
+			connect(tag)
+		}
+	}
+	return components
+}
+
+// elementaryCycles is Johnson's algorithm listing every cycle once, starting from its smallest tag, up to maxCyclesPerComponent.
+// Vertices which cannot reach start stay blocked, so work between two found cycles is linear in size of component.
+func elementaryCycles(component []string, neighbours map[string][]string) [][]string {
+	cycles := [][]string{}
+	for i, start := range component {
+		allowed := map[string]bool{}
+		for _, tag := range component[i:] {
+			allowed[tag] = true
+		}
+		path := []string{}
+		blocked := map[string]bool{}
+		blockedBy := map[string]map[string]bool{}
+
+		var unblock func(tag string)
+		unblock = func(tag string) {
+			blocked[tag] = false
+			for waiting := range blockedBy[tag] {
+				delete(blockedBy[tag], waiting)
+				if blocked[waiting] {
+					unblock(waiting)
+				}
+			}
+		}
+
+		var circuit func(tag string) bool
+		circuit = func(tag string) bool {
+			found := false
+			path = append(path, tag)
+			blocked[tag] = true
+			for _, next := range neighbours[tag] {
+				if len(cycles) == maxCyclesPerComponent {
+					break
+				}
+				if !allowed[next] {
+					continue
+				}
+				if next == start {
+					cycles = append(cycles, append(slices.Clone(path), start))
+					found = true
+				} else if !blocked[next] && circuit(next) {
+					found = true
+				}
+			}
+			if found {
+				unblock(tag)
+			} else {
+				for _, next := range neighbours[tag] {
+					if allowed[next] {
+						if blockedBy[next] == nil {
+							blockedBy[next] = map[string]bool{}
+						}
+						blockedBy[next][tag] = true
+					}
+				}
+			}
+			path = path[:len(path)-1]
+			return found
+		}
+		circuit(start)
+		if len(cycles) == maxCyclesPerComponent {
+			break
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func graphOf(edges map[string][]string) []Resource {
+	resources := []Resource{}
+	for _, source := range sortedKeys(edges) {
+		resource := Resource{Tag: source, References: map[string][]Reference{}}
+		for _, target := range edges[source] {
+			resource.References[target] = []Reference{{Path: source + ".go"}}
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func TestFindCycles(t *testing.T) {
+	resources := graphOf(map[string][]string{
+		"a": {"b", "x"},
+		"b": {"a"},
+		"c": {"d", "e"},
+		"d": {"e"},
+		"e": {"c"},
+		"f": {"f"},
+		"g": {"h"},
+		"x": {"a"},
+	})
+
+	cycles := FindCycles(resources, []string{"x"}, nil)
+	got := map[string][][]string{}
+	for _, cycle := range cycles {
+		got[fmt.Sprint(cycle.Tags)] = cycle.Paths
+	}
+	want := map[string][][]string{
+		"[a b]":   {{"a", "b", "a"}},
+		"[c d e]": {{"c", "d", "e", "c"}, {"c", "e", "c"}},
+		"[f]":     {{"f", "f"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if edges := cycles[0].Edges; len(edges) != 2 || edges[0].Source != "a" || edges[0].Target != "b" || len(edges[0].References) != 1 {
+		t.Errorf("unexpected edges of first cycle %+v", edges)
+	}
+
+	if got := FindCycles(resources, nil, []string{"c", "d", "e"}); len(got) != 1 || !reflect.DeepEqual(got[0].Tags, []string{"c", "d", "e"}) {
+		t.Errorf("valid tags not applied: %+v", got)
+	}
+	if got := FindCycles(resources, nil, nil); len(got) != 3 || !reflect.DeepEqual(got[0].Tags, []string{"a", "b", "x"}) {
+		t.Errorf("got %+v, want a, b and x in one component", got)
+	}
+}
+
+func TestCycleAllowed(t *testing.T) {
+	cycle := Cycle{Tags: []string{"a", "b"}}
+	for _, tc := range []struct {
+		allowlist [][]string
+		want      bool
+	}{
+		{[][]string{{"a", "b", "c"}}, true},
+		{[][]string{{"a"}, {"b"}}, false},
+		{nil, false},
+	} {
+		if got := cycle.Allowed(tc.allowlist); got != tc.want {
+			t.Errorf("Allowed(%v) = %v, want %v", tc.allowlist, got, tc.want)
+		}
+	}
+}
+
+// TestElementaryCyclesDense has single cycle through a, while clique behind it has factorial number of paths
+// not leading back to a. Search must not walk them all before cap on cycles is reached.
+func TestElementaryCyclesDense(t *testing.T) {
+	edges := map[string][]string{"a": {"b"}, "b": {"c00"}}
+	clique := []string{}
+	for i := 0; i < 16; i++ {
+		clique = append(clique, fmt.Sprintf("c%02d", i))
+	}
+	for _, source := range clique {
+		for _, target := range clique {
+			if source != target {
+				edges[source] = append(edges[source], target)
+			}
+		}
+	}
+	edges["c00"] = append(edges["c00"], "a")
+
+	done := make(chan []Cycle, 1)
+	go func() { done <- FindCycles(graphOf(edges), nil, nil) }()
+	select {
+	case cycles := <-done:
+		if len(cycles) != 1 || len(cycles[0].Paths) != maxCyclesPerComponent {
+			t.Fatalf("got %d components, want one with %d cycles", len(cycles), maxCyclesPerComponent)
+		}
+		paths := cycles[0].Paths
+		if !reflect.DeepEqual(paths[0], []string{"a", "b", "c00", "a"}) {
+			t.Errorf("first cycle %v, want a -> b -> c00 -> a", paths[0])
+		}
+		seen := map[string]bool{}
+		for _, path := range paths {
+			key := fmt.Sprint(path)
+			if seen[key] || path[0] != path[len(path)-1] || slices.Contains(path[1:len(path)-1], path[0]) {
+				t.Errorf("invalid or duplicate cycle %v", path)
+			}
+			seen[key] = true
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cycle enumeration did not finish")
+	}
+}
@@ -0,0 +1,211 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Detector finds software used by repository in files it matches.
+type Detector interface {
+	// Name identifies detector in warnings and cache keys.
+	Name() string
+	// Matches reports whether file, given by slash separated path relative to repository root, is inspected.
+	Matches(path string) bool
+	// Extract returns raw values found in file, e.g. image names or labelled versions.
+	Extract(file SourceFile) []string
+	// Normalize maps raw value to software label. Empty label drops value, false marks value unknown.
+	Normalize(value string) (string, bool)
+}
+
+// SourceFile is inspected file of repository. Other files of the same repository are available with Read,
+// file is rescanned whenever any of them changes.
+type SourceFile struct {
+	Path    string
+	Content []byte
+	root    string
+	inputs  *[]string
+}
+
+// Read returns content of file given by slash separated path relative to repository root.
+func (file SourceFile) Read(name string) ([]byte, error) {
+	name = path.Clean(name)
+	if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+		return nil, errors.New("path outside of repository: " + name)
+	}
+	if file.inputs != nil && !slices.Contains(*file.inputs, name) {
+		*file.inputs = append(*file.inputs, name)
+	}
+	return os.ReadFile(filepath.Join(file.root, filepath.FromSlash(name)))
+}
+
+// Dir is directory of file relative to repository root, "." for root.
+func (file SourceFile) Dir() string {
+	return path.Dir(file.Path)
+}
+
+// DetectorConfig is declarative detector from config.json. Every match of Regexp in files matching any of Files globs
+// is expanded with Template (regexp.Expand syntax, by default first capture group) and mapped with Mapping when set.
+// Values missing in non-empty Mapping are reported as unknown.
+type DetectorConfig struct {
+	Name     string            `json:"name"`
+	Files    []string          `json:"files"`
+	Regexp   *regexp.Regexp    `json:"reg"`
+	Template string            `json:"template"`
+	Mapping  map[string]string `json:"mapping"`
+}
+
+// passthroughNormalize is embedded by detectors reporting values as they were extracted.
+type passthroughNormalize struct{}
+
+func (passthroughNormalize) Normalize(value string) (string, bool) {
+	return value, true
+}
+
+// regexpDetector runs DetectorConfig declared in config.json.
+type regexpDetector struct {
+	DetectorConfig
+}
+
+func (d regexpDetector) Name() string {
+	return d.DetectorConfig.Name
+}
+
+func (d regexpDetector) Matches(path string) bool {
+	return matchesAny(d.Files, path)
+}
+
+func (d regexpDetector) Extract(file SourceFile) []string {
+	template := d.Template
+	if len(template) == 0 {
+		template = "$0"
+		if d.Regexp.NumSubexp() > 0 {
+			template = "$1"
+		}
+	}
+	values := []string{}
+	for _, match := range d.Regexp.FindAllSubmatchIndex(file.Content, -1) {
+		value := string(d.Regexp.Expand(nil, []byte(template), file.Content, match))
+		if len(strings.TrimSpace(value)) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (d regexpDetector) Normalize(value string) (string, bool) {
+	if len(d.Mapping) == 0 {
+		return value, true
+	}
+	label, ok := d.Mapping[value]
+	return label, ok
+}
+
+var mapping = map[string]string{
+	"adoptopenjdk/openjdk11":      "Java 11",
+	"eclipse-temurin:17-jre":      "Java 17",
+	"eclipse-temurin:17":          "Java 17",
+	"python:3.10-slim":            "Python 3.10",
+	"adoptopenjdk:11-jre-hotspot": "Java 11",
+	"eclipse-temurin:19-jre":      "Java 19",
+	"node:16-alpine":              "Node 16",
+	"nginx:1":                     "",
+	"alpine:latest":               "",
+	"node:16":                     "Node 16",
+	"node:14":                     "Node 14",
+	"node:14-alpine":              "Node 14",
+	"python:3":                    "Python 3",
+	"sonarqube:9":                 "",
+	"cypress/included:12":         "",
+	"cypress/included:10":         "",
+	"cypress/included:13":         "",
+	"continuumio/miniconda3:4":    "",
+	"ubuntu:18":                   "",
+	"golang:alpine":               "Golang",
+	"phlptp/units:webserver":      "C++",
+	"node:16-buster-slim":         "Node 16",
+	"gradle:7":                    "",
+	"eclipse-temurin:17-jdk":      "Java 17",
+	"debian:buster-slim":          "",
+	"node:18":                     "Node 18",
+	"postgres:13":                 "",
+}
+
+// resolveDetectors returns built-in detectors followed by configured ones. Images extend built-in image mapping.
+func resolveDetectors(config Config) ([]Detector, error) {
+	images := maps.Clone(mapping)
+	maps.Copy(images, config.Images)
+
+	detectors := []Detector{
+		dockerDetector{mapping: images},
+		gradleDetector{},
+		gradlePropertiesDetector{},
+		versionCatalogDetector{},
+		mavenDetector{},
+		frontendDetector{},
+		nodeVersionDetector{},
+		pythonDetector{},
+		goModDetector{},
+		dotnetDetector{},
+		rubyDetector{},
+		rustDetector{},
+	}
+	for i, d := range config.Detectors {
+		if d.Regexp == nil {
+			return nil, fmt.Errorf("detector %d (%s) has no regexp", i, d.Name)
+		}
+		if len(d.Files) == 0 {
+			return nil, fmt.Errorf("detector %d (%s) has no files", i, d.Name)
+		}
+		detectors = append(detectors, regexpDetector{d})
+	}
+	return detectors, nil
+}
+
+// softwareLabel joins name and version, spaces are removed from version so it stays single token for ParseSoftware.
+func softwareLabel(name string, version string) string {
+	version = strings.ReplaceAll(strings.TrimSpace(version), " ", "")
+	if len(version) == 0 {
+		return name
+	}
+	return name + " " + version
+}
+
+// detectSoftware runs detectors matching file, content is read only when any of them does.
+func detectSoftware(path string, relativePath string, source checkout, executionConfig ExecutionConfig) (FileFindings, error) {
+	findings := FileFindings{}
+	file := SourceFile{Path: relativePath, root: source.path, inputs: &findings.Inputs}
+	for _, detector := range executionConfig.Detectors {
+		if !detector.Matches(relativePath) {
+			continue
+		}
+		if file.Content == nil {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return FileFindings{}, err
+			}
+			file.Content = data
+		}
+		if d, ok := detector.(imageDetector); ok {
+			findings.Images = append(findings.Images, d.images(file)...)
+		}
+		for _, value := range detector.Extract(file) {
+			label, known := detector.Normalize(value)
+			if !known {
+				executionConfig.emit(Event{Type: EventWarning, Repository: source.repository.Name, Message: fmt.Sprintf("Unknown %s value %s in %s/%s", detector.Name(), value, source.repository.Name, relativePath)})
+				label = value
+			}
+			if len(label) > 0 {
+				findings.Software = append(findings.Software, label)
+			}
+		}
+	}
+	findings.Software = unique(findings.Software)
+	return findings, nil
+}
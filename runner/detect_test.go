@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRegexpDetector(t *testing.T) {
+	content := []byte("ruby '3.2.2'\nruby '2.7'\nruby ''\n")
+	for _, tc := range []struct {
+		name   string
+		config DetectorConfig
+		want   []string
+	}{
+		{"first group", DetectorConfig{Regexp: regexp.MustCompile(`ruby '([\d.]*)'`)}, []string{"3.2.2", "2.7"}},
+		{"whole match", DetectorConfig{Regexp: regexp.MustCompile(`ruby '[\d.]+'`)}, []string{"ruby '3.2.2'", "ruby '2.7'"}},
+		{"template", DetectorConfig{Regexp: regexp.MustCompile(`(ruby) '(\d+)\.(\d+)`), Template: "Ruby ${2}.$3"}, []string{"Ruby 3.2", "Ruby 2.7"}},
+		{"mapping", DetectorConfig{Regexp: regexp.MustCompile(`ruby '(\d+)`), Mapping: map[string]string{"3": "Ruby 3", "2": ""}}, []string{"Ruby 3"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := regexpDetector{tc.config}
+			got := []string{}
+			for _, value := range d.Extract(SourceFile{Content: content}) {
+				if label, known := d.Normalize(value); known && len(label) > 0 {
+					got = append(got, label)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if _, known := (regexpDetector{DetectorConfig{Mapping: map[string]string{"3": "Ruby 3"}}}).Normalize("4"); known {
+		t.Error("value missing in mapping is known")
+	}
+}
+
+func TestResolveDetectors(t *testing.T) {
+	reg := regexp.MustCompile(`x`)
+	for _, tc := range []struct {
+		name    string
+		config  DetectorConfig
+		wantErr bool
+	}{
+		{"valid", DetectorConfig{Name: "x", Files: []string{"*.x"}, Regexp: reg}, false},
+		{"no regexp", DetectorConfig{Name: "x", Files: []string{"*.x"}}, true},
+		{"no files", DetectorConfig{Name: "x", Regexp: reg}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			detectors, err := resolveDetectors(Config{Detectors: []DetectorConfig{tc.config}})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v", err)
+			}
+			if err == nil && detectors[len(detectors)-1].Name() != "x" {
+				t.Error("configured detector is not last")
+			}
+		})
+	}
+}
+
+func TestSoftwareLabel(t *testing.T) {
+	for _, tc := range []struct{ name, version, want string }{
+		{"Java", "17", "Java 17"},
+		{"Java", " ", "Java"},
+		{"Node", ">= 18 < 21", "Node >=18<21"},
+	} {
+		if got := softwareLabel(tc.name, tc.version); got != tc.want {
+			t.Errorf("softwareLabel(%q, %q) = %q, want %q", tc.name, tc.version, got, tc.want)
+		}
+	}
+}
+
+// versionFileDetector reports version kept in separate file, as detectors reading other files do.
+type versionFileDetector struct{ passthroughNormalize }
+
+func (versionFileDetector) Name() string { return "version-file" }
+
+func (versionFileDetector) Matches(path string) bool { return filepath.Base(path) == "app.cfg" }
+
+func (versionFileDetector) Extract(file SourceFile) []string {
+	data, err := file.Read(file.Dir() + "/VERSION")
+	if err != nil {
+		return nil
+	}
+	return []string{"App " + string(data)}
+}
+
+func TestDetectSoftware(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"svc/app.cfg": "lang = ruby '3.2.2'\nlang = ruby '1.9'\n",
+		"svc/VERSION": "4.0",
+	})
+	config := Config{Detectors: []DetectorConfig{{
+		Name:    "ruby",
+		Files:   []string{"*.cfg"},
+		Regexp:  regexp.MustCompile(`ruby '(\d+)`),
+		Mapping: map[string]string{"3": "Ruby 3"},
+	}}}
+	executionConfig := testExecutionConfig(t, config)
+	executionConfig.Detectors = append(executionConfig.Detectors, versionFileDetector{})
+	warnings := []string{}
+	executionConfig.progress = func(event Event) {
+		if event.Type == EventWarning {
+			warnings = append(warnings, event.Message)
+		}
+	}
+	source := checkout{repository: Repository{Name: "repo"}, path: root}
+
+	findings, err := detectSoftware(filepath.Join(root, "svc", "app.cfg"), "svc/app.cfg", source, executionConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FileFindings{Software: []string{"Ruby 3", "1", "App 4.0"}, Inputs: []string{"svc/VERSION"}}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("got %+v, want %+v", findings, want)
+	}
+	if want := []string{"Unknown ruby value 1 in repo/svc/app.cfg"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("got warnings %q, want %q", warnings, want)
+	}
+
+	if _, err := (SourceFile{root: root}).Read("../outside"); err == nil {
+		t.Error("read outside of repository")
+	}
+}
@@ -0,0 +1,293 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+func (e Edge) String() string {
+	return fmt.Sprintf("%s ---> %s", e.Source, e.Target)
+}
+
+// SoftwareChange of single technology of resource, From is empty when added and To when removed.
+type SoftwareChange struct {
+	Tag  string `json:"tag"`
+	Name string `json:"name"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// MovedReferences lists locations of edge present in both snapshots which disappeared or appeared.
+type MovedReferences struct {
+	Edge
+	Removed []string `json:"removed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+}
+
+type Diff struct {
+	AddedResources   []string          `json:"addedResources"`
+	RemovedResources []string          `json:"removedResources"`
+	AddedEdges       []Edge            `json:"addedEdges"`
+	RemovedEdges     []Edge            `json:"removedEdges"`
+	SoftwareChanges  []SoftwareChange  `json:"softwareChanges"`
+	MovedReferences  []MovedReferences `json:"movedReferences"`
+}
+
+func (d Diff) Empty() bool {
+	return len(d.AddedResources) == 0 && len(d.RemovedResources) == 0 && len(d.AddedEdges) == 0 &&
+		len(d.RemovedEdges) == 0 && len(d.SoftwareChanges) == 0 && len(d.MovedReferences) == 0
+}
+
+// ParseSoftware splits detected software into name and version, e.g. "Spring 2.7.2" or raw image "node:21".
+// Installed version following declared one, e.g. "React ^18.2.0 (18.2.1)", stays part of version.
+func ParseSoftware(software string) (string, string) {
+	installed := ""
+	if i := strings.LastIndex(software, " ("); i > 0 && strings.HasSuffix(software, ")") {
+		software, installed = software[:i], software[i:]
+	}
+	if i := strings.LastIndex(software, " "); i > 0 {
+		return software[:i], software[i+1:] + installed
+	}
+	if i := strings.LastIndex(software, ":"); i > 0 {
+		return software[:i], software[i+1:] + installed
+	}
+	return software, strings.TrimSpace(installed)
+}
+
+// DiffOutputs compares two snapshots of analyzer output.
+func DiffOutputs(oldResources []Resource, newResources []Resource) Diff {
+	oldByTag := resourcesByTag(oldResources)
+	newByTag := resourcesByTag(newResources)
+
+	diff := Diff{
+		AddedResources:   []string{},
+		RemovedResources: []string{},
+		AddedEdges:       []Edge{},
+		RemovedEdges:     []Edge{},
+		SoftwareChanges:  []SoftwareChange{},
+		MovedReferences:  []MovedReferences{},
+	}
+
+	for _, tag := range sortedKeys(newByTag) {
+		if _, ok := oldByTag[tag]; !ok {
+			diff.AddedResources = append(diff.AddedResources, tag)
+		}
+	}
+	for _, tag := range sortedKeys(oldByTag) {
+		if _, ok := newByTag[tag]; !ok {
+			diff.RemovedResources = append(diff.RemovedResources, tag)
+		}
+	}
+
+	for _, tag := range sortedKeys(newByTag) {
+		newResource := newByTag[tag]
+		oldResource := oldByTag[tag]
+		for _, target := range sortedKeys(newResource.References) {
+			oldRefs, ok := oldResource.References[target]
+			if !ok {
+				diff.AddedEdges = append(diff.AddedEdges, Edge{tag, target})
+				continue
+			}
+			removed, added := diffLocations(oldRefs, newResource.References[target])
+			if len(removed) > 0 || len(added) > 0 {
+				diff.MovedReferences = append(diff.MovedReferences, MovedReferences{Edge{tag, target}, removed, added})
+			}
+		}
+	}
+	for _, tag := range sortedKeys(oldByTag) {
+		for _, target := range sortedKeys(oldByTag[tag].References) {
+			if _, ok := newByTag[tag].References[target]; !ok {
+				diff.RemovedEdges = append(diff.RemovedEdges, Edge{tag, target})
+			}
+		}
+	}
+
+	for _, tag := range sortedKeys(newByTag) {
+		if oldResource, ok := oldByTag[tag]; ok {
+			diff.SoftwareChanges = append(diff.SoftwareChanges, diffSoftware(tag, oldResource.Software, newByTag[tag].Software)...)
+		}
+	}
+
+	return diff
+}
+
+func resourcesByTag(resources []Resource) map[string]Resource {
+	byTag := map[string]Resource{}
+	for _, r := range resources {
+		byTag[r.Tag] = r
+	}
+	return byTag
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// diffLocations compares file:line locations, commit and column changes alone are not a move.
+func diffLocations(oldRefs []Reference, newRefs []Reference) ([]string, []string) {
+	oldLocations := []string{}
+	for _, ref := range oldRefs {
+		oldLocations = append(oldLocations, ref.String())
+	}
+	newLocations := []string{}
+	for _, ref := range newRefs {
+		newLocations = append(newLocations, ref.String())
+	}
+
+	removed := []string{}
+	for _, location := range unique(oldLocations) {
+		if !slices.Contains(newLocations, location) {
+			removed = append(removed, location)
+		}
+	}
+	added := []string{}
+	for _, location := range unique(newLocations) {
+		if !slices.Contains(oldLocations, location) {
+			added = append(added, location)
+		}
+	}
+	slices.Sort(removed)
+	slices.Sort(added)
+	return removed, added
+}
+
+func diffSoftware(tag string, oldSoftware []string, newSoftware []string) []SoftwareChange {
+	oldVersions := softwareVersions(oldSoftware)
+	newVersions := softwareVersions(newSoftware)
+
+	changes := []SoftwareChange{}
+	for _, name := range sortedKeys(newVersions) {
+		from := strings.Join(oldVersions[name], ", ")
+		to := strings.Join(newVersions[name], ", ")
+		if from != to {
+			changes = append(changes, SoftwareChange{Tag: tag, Name: name, From: from, To: to})
+		}
+	}
+	for _, name := range sortedKeys(oldVersions) {
+		if _, ok := newVersions[name]; !ok {
+			changes = append(changes, SoftwareChange{Tag: tag, Name: name, From: strings.Join(oldVersions[name], ", ")})
+		}
+	}
+	return changes
+}
+
+func softwareVersions(software []string) map[string][]string {
+	versions := map[string][]string{}
+	for _, s := range software {
+		name, version := ParseSoftware(s)
+		versions[name] = append(versions[name], version)
+	}
+	for name := range versions {
+		slices.Sort(versions[name])
+	}
+	return versions
+}
+
+func (d Diff) Text() string {
+	text := ""
+	for _, tag := range d.AddedResources {
+		text += fmt.Sprintf("+ resource %s\n", tag)
+	}
+	for _, tag := range d.RemovedResources {
+		text += fmt.Sprintf("- resource %s\n", tag)
+	}
+	for _, edge := range d.AddedEdges {
+		text += fmt.Sprintf("+ edge %s\n", edge)
+	}
+	for _, edge := range d.RemovedEdges {
+		text += fmt.Sprintf("- edge %s\n", edge)
+	}
+	for _, change := range d.SoftwareChanges {
+		text += fmt.Sprintf("~ software %s: %s\n", change.Tag, change.describe())
+	}
+	for _, moved := range d.MovedReferences {
+		text += fmt.Sprintf("~ references %s\n", moved.Edge)
+		for _, location := range moved.Removed {
+			text += fmt.Sprintf("\t- %s\n", location)
+		}
+		for _, location := range moved.Added {
+			text += fmt.Sprintf("\t+ %s\n", location)
+		}
+	}
+	if len(text) == 0 {
+		text = "No changes\n"
+	}
+	return text
+}
+
+func (d Diff) Markdown() string {
+	md := "# Changes\n\n"
+	if d.Empty() {
+		return md + "No changes\n"
+	}
+	md += markdownList("Added resources", d.AddedResources)
+	md += markdownList("Removed resources", d.RemovedResources)
+
+	edges := []string{}
+	for _, edge := range d.AddedEdges {
+		edges = append(edges, fmt.Sprintf("`%s` ---> `%s`", edge.Source, edge.Target))
+	}
+	md += markdownList("Added edges", edges)
+
+	edges = []string{}
+	for _, edge := range d.RemovedEdges {
+		edges = append(edges, fmt.Sprintf("`%s` ---> `%s`", edge.Source, edge.Target))
+	}
+	md += markdownList("Removed edges", edges)
+
+	if len(d.SoftwareChanges) > 0 {
+		md += "## Software changes\n\n| Resource | Software | From | To |\n| --- | --- | --- | --- |\n"
+		for _, change := range d.SoftwareChanges {
+			md += fmt.Sprintf("| %s | %s | %s | %s |\n", change.Tag, change.Name, change.From, change.To)
+		}
+		md += "\n"
+	}
+
+	if len(d.MovedReferences) > 0 {
+		md += "## Moved references\n\n"
+		for _, moved := range d.MovedReferences {
+			md += fmt.Sprintf("### `%s` ---> `%s`\n\n", moved.Source, moved.Target)
+			for _, location := range moved.Removed {
+				md += fmt.Sprintf("- ~~%s~~\n", location)
+			}
+			for _, location := range moved.Added {
+				md += fmt.Sprintf("- %s\n", location)
+			}
+			md += "\n"
+		}
+	}
+	return md
+}
+
+func markdownList(title string, items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	md := fmt.Sprintf("## %s\n\n", title)
+	for _, item := range items {
+		md += fmt.Sprintf("- %s\n", item)
+	}
+	return md + "\n"
+}
+
+func (c SoftwareChange) describe() string {
+	switch {
+	case len(c.From) == 0:
+		return fmt.Sprintf("added %s %s", c.Name, c.To)
+	case len(c.To) == 0:
+		return fmt.Sprintf("removed %s %s", c.Name, c.From)
+	default:
+		return fmt.Sprintf("%s %s -> %s", c.Name, c.From, c.To)
+	}
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSoftware(t *testing.T) {
+	tests := []struct {
+		software string
+		name     string
+		version  string
+	}{
+		{"Spring 2.7.2", "Spring", "2.7.2"},
+		{"Spring Boot 3.1.0", "Spring Boot", "3.1.0"},
+		{"node:21", "node", "21"},
+		{"React ^18.2.0 (18.2.1)", "React", "^18.2.0 (18.2.1)"},
+		{"Docker", "Docker", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.software, func(t *testing.T) {
+			name, version := ParseSoftware(tt.software)
+			if name != tt.name || version != tt.version {
+				t.Errorf("got %q %q, want %q %q", name, version, tt.name, tt.version)
+			}
+		})
+	}
+}
+
+func TestDiffOutputs(t *testing.T) {
+	ref := func(path string, line int, commit string) Reference {
+		return Reference{Repository: "a", Path: path, Line: line, Commit: commit}
+	}
+	tests := []struct {
+		name     string
+		old, new []Resource
+		want     Diff
+	}{
+		{
+			name: "identical",
+			old:  []Resource{{Tag: "a", References: map[string][]Reference{"b": {ref("main.go", 1, "c1")}}, Software: []string{"Go 1.21"}}},
+			new:  []Resource{{Tag: "a", References: map[string][]Reference{"b": {ref("main.go", 1, "c2")}}, Software: []string{"Go 1.21"}}},
+			want: Diff{},
+		},
+		{
+			name: "resources and edges",
+			old:  []Resource{{Tag: "a", References: map[string][]Reference{"b": {ref("main.go", 1, "")}}}, {Tag: "b"}},
+			new:  []Resource{{Tag: "a", References: map[string][]Reference{"c": {ref("main.go", 1, "")}}}, {Tag: "c"}},
+			want: Diff{
+				AddedResources:   []string{"c"},
+				RemovedResources: []string{"b"},
+				AddedEdges:       []Edge{{"a", "c"}},
+				RemovedEdges:     []Edge{{"a", "b"}},
+			},
+		},
+		{
+			name: "moved references",
+			old:  []Resource{{Tag: "a", References: map[string][]Reference{"b": {ref("main.go", 1, ""), ref("util.go", 5, "")}}}},
+			new:  []Resource{{Tag: "a", References: map[string][]Reference{"b": {ref("main.go", 1, ""), ref("util.go", 7, "")}}}},
+			want: Diff{MovedReferences: []MovedReferences{{Edge{"a", "b"}, []string{"a/util.go:5"}, []string{"a/util.go:7"}}}},
+		},
+		{
+			name: "software",
+			old:  []Resource{{Tag: "a", Software: []string{"Spring 2.7.2", "Java 17", "node:18"}}},
+			new:  []Resource{{Tag: "a", Software: []string{"Spring 3.1.0", "Java 17", "Kotlin 1.9"}}},
+			want: Diff{SoftwareChanges: []SoftwareChange{
+				{Tag: "a", Name: "Kotlin", To: "1.9"},
+				{Tag: "a", Name: "Spring", From: "2.7.2", To: "3.1.0"},
+				{Tag: "a", Name: "node", From: "18"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffOutputs(tt.old, tt.new)
+			want := Diff{
+				AddedResources:   append([]string{}, tt.want.AddedResources...),
+				RemovedResources: append([]string{}, tt.want.RemovedResources...),
+				AddedEdges:       append([]Edge{}, tt.want.AddedEdges...),
+				RemovedEdges:     append([]Edge{}, tt.want.RemovedEdges...),
+				SoftwareChanges:  append([]SoftwareChange{}, tt.want.SoftwareChanges...),
+				MovedReferences:  append([]MovedReferences{}, tt.want.MovedReferences...),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+			if got.Empty() != (tt.name == "identical") {
+				t.Errorf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// BaseImage of single Dockerfile stage. Stage based on earlier stage names it in FromStage and repeats its image.
+// Last stage of file is the Runtime one.
+type BaseImage struct {
+	Path      string `json:"path"`
+	Stage     string `json:"stage,omitempty"`
+	Image     string `json:"image"`
+	Registry  string `json:"registry,omitempty"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag,omitempty"`
+	Digest    string `json:"digest,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	FromStage string `json:"fromStage,omitempty"`
+	Runtime   bool   `json:"runtime,omitempty"`
+}
+
+// imageDetector is implemented by detectors which also report base images.
+type imageDetector interface {
+	images(file SourceFile) []BaseImage
+}
+
+// dockerDetector parses Dockerfiles and maps base images to labels.
+type dockerDetector struct {
+	mapping map[string]string
+}
+
+func (dockerDetector) Name() string {
+	return "docker"
+}
+
+func (dockerDetector) Matches(path string) bool {
+	return matchesAny([]string{"Dockerfile", "Dockerfile.*", "*.dockerfile"}, path)
+}
+
+func (d dockerDetector) Extract(file SourceFile) []string {
+	values := []string{}
+	for _, image := range d.images(file) {
+		if len(image.FromStage) == 0 && image.Name != "scratch" {
+			values = append(values, image.Image)
+		}
+	}
+	return values
+}
+
+// Normalize looks image up with registry, without it, with tag cut to leading `[A-Za-z0-9-]` characters as matched
+// by former regexp based detection, and without tag.
+func (d dockerDetector) Normalize(value string) (string, bool) {
+	image := parseImage(value)
+	nameTag := image.Name
+	if len(image.Tag) > 0 {
+		nameTag += ":" + image.Tag
+	}
+	candidates := []string{value, nameTag}
+	if legacy := legacyTagReg.FindString(image.Tag); len(legacy) > 0 {
+		candidates = append(candidates, image.Name+":"+legacy)
+	}
+	candidates = append(candidates, image.Name)
+	for _, candidate := range candidates {
+		if label, ok := d.mapping[candidate]; ok {
+			return label, true
+		}
+	}
+	return value, false
+}
+
+var legacyTagReg = regexp.MustCompile("^[A-Za-z0-9-]+")
+
+func (dockerDetector) images(file SourceFile) []BaseImage {
+	args := map[string]string{}
+	images := []BaseImage{}
+	for _, instruction := range dockerInstructions(file.Content) {
+		keyword, rest, _ := strings.Cut(instruction, " ")
+		fields := strings.Fields(rest)
+		switch strings.ToUpper(keyword) {
+		case "ARG":
+			// Only ARGs declared before first FROM can be used in FROM.
+			if len(images) > 0 {
+				continue
+			}
+			for _, field := range fields {
+				// ARG without default stays unresolved.
+				if name, value, found := strings.Cut(field, "="); found {
+					args[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			image := BaseImage{Path: file.Path}
+			reference := ""
+			for i := 0; i < len(fields); i++ {
+				field := fields[i]
+				switch {
+				case strings.HasPrefix(field, "--platform="):
+					image.Platform = expandArgs(strings.TrimPrefix(field, "--platform="), args)
+				case strings.HasPrefix(field, "--"):
+				case strings.EqualFold(field, "AS") && i+1 < len(fields):
+					image.Stage = fields[i+1]
+					i++
+				case len(reference) == 0:
+					reference = expandArgs(field, args)
+				}
+			}
+			if len(reference) == 0 {
+				continue
+			}
+			if stage := findStage(images, reference); stage != nil {
+				image.Image, image.Registry, image.Name, image.Tag, image.Digest = stage.Image, stage.Registry, stage.Name, stage.Tag, stage.Digest
+				image.FromStage = stage.Stage
+			} else {
+				parsed := parseImage(reference)
+				image.Image, image.Registry, image.Name, image.Tag, image.Digest = reference, parsed.Registry, parsed.Name, parsed.Tag, parsed.Digest
+			}
+			images = append(images, image)
+		}
+	}
+	if len(images) > 0 {
+		images[len(images)-1].Runtime = true
+	}
+	return images
+}
+
+// dockerInstructions joins continuation lines and drops comments and empty lines.
+func dockerInstructions(content []byte) []string {
+	instructions := []string{}
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || len(line) == 0 {
+			continue
+		}
+		if continued, ok := strings.CutSuffix(line, `\`); ok {
+			current += continued + " "
+			continue
+		}
+		instructions = append(instructions, current+line)
+		current = ""
+	}
+	if len(current) > 0 {
+		instructions = append(instructions, current)
+	}
+	return instructions
+}
+
+// findStage returns earlier stage named as reference, stage names are case insensitive.
+func findStage(images []BaseImage, reference string) *BaseImage {
+	for i := len(images) - 1; i >= 0; i-- {
+		if len(images[i].Stage) > 0 && strings.EqualFold(images[i].Stage, reference) {
+			return &images[i]
+		}
+	}
+	return nil
+}
+
+var argReg = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::([-+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// expandArgs substitutes $NAME, ${NAME}, ${NAME:-default} and ${NAME:+value}, unknown args are left as written.
+func expandArgs(value string, args map[string]string) string {
+	return argReg.ReplaceAllStringFunc(value, func(match string) string {
+		groups := argReg.FindStringSubmatch(match)
+		name := groups[1] + groups[4]
+		arg, ok := args[name]
+		switch groups[2] {
+		case "-":
+			if len(arg) == 0 {
+				return groups[3]
+			}
+		case "+":
+			if len(arg) > 0 {
+				return groups[3]
+			}
+			return ""
+		}
+		if !ok {
+			return match
+		}
+		return arg
+	})
+}
+
+// parseImage splits reference into registry, name, tag and digest. First path component is registry
+// when it contains dot or port or is localhost.
+func parseImage(reference string) BaseImage {
+	image := BaseImage{Image: reference}
+	rest := reference
+	if before, digest, ok := strings.Cut(rest, "@"); ok {
+		rest, image.Digest = before, digest
+	}
+	if first, remainder, ok := strings.Cut(rest, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		image.Registry, rest = first, remainder
+	}
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest, image.Tag = rest[:i], rest[i+1:]
+	}
+	image.Name = rest
+	return image
+}
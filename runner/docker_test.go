@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDockerImages(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    []BaseImage
+	}{
+		{
+			name:    "single stage",
+			content: "# syntax=docker/dockerfile:1\nFROM node:20-alpine\nRUN npm ci\n",
+			want:    []BaseImage{{Image: "node:20-alpine", Name: "node", Tag: "20-alpine", Runtime: true}},
+		},
+		{
+			name: "multi-stage",
+			content: `FROM --platform=$BUILDPLATFORM golang:1.21 AS build
+RUN go build
+FROM build as test
+FROM gcr.io/distroless/static@sha256:abc AS runtime
+COPY --from=build /app /app
+`,
+			want: []BaseImage{
+				{Stage: "build", Image: "golang:1.21", Name: "golang", Tag: "1.21", Platform: "$BUILDPLATFORM"},
+				{Stage: "test", Image: "golang:1.21", Name: "golang", Tag: "1.21", FromStage: "build"},
+				{Stage: "runtime", Image: "gcr.io/distroless/static@sha256:abc", Registry: "gcr.io", Name: "distroless/static", Digest: "sha256:abc", Runtime: true},
+			},
+		},
+		{
+			name: "args",
+			content: `ARG REGISTRY=registry.example.com:5000
+ARG VERSION="17"
+ARG VARIANT
+FROM ${REGISTRY}/eclipse-temurin:${VERSION}-jre AS base
+ARG VERSION=21
+FROM $REGISTRY/base:${VARIANT:-slim}
+FROM node:${VARIANT}
+FROM python:3${VARIANT:+-$VARIANT}
+`,
+			want: []BaseImage{
+				{Stage: "base", Image: "registry.example.com:5000/eclipse-temurin:17-jre", Registry: "registry.example.com:5000", Name: "eclipse-temurin", Tag: "17-jre"},
+				{Image: "registry.example.com:5000/base:slim", Registry: "registry.example.com:5000", Name: "base", Tag: "slim"},
+				{Image: "node:${VARIANT}", Name: "node", Tag: "${VARIANT}"},
+				{Image: "python:3", Name: "python", Tag: "3", Runtime: true},
+			},
+		},
+		{
+			name:    "continuation lines and scratch",
+			content: "FROM \\\n  localhost/app:1 \\\n  AS app\nFROM scratch\n",
+			want: []BaseImage{
+				{Stage: "app", Image: "localhost/app:1", Registry: "localhost", Name: "app", Tag: "1"},
+				{Image: "scratch", Name: "scratch", Runtime: true},
+			},
+		},
+		{
+			name:    "no FROM",
+			content: "RUN true\n",
+			want:    []BaseImage{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := range tc.want {
+				tc.want[i].Path = "Dockerfile"
+			}
+			got := dockerDetector{}.images(SourceFile{Path: "Dockerfile", Content: []byte(tc.content)})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDockerExtractAndNormalize(t *testing.T) {
+	detector := dockerDetector{mapping: map[string]string{"node": "Node", "eclipse-temurin:21": "Java 21"}}
+	content := "FROM node:20-alpine AS build\nFROM build\nFROM scratch\nFROM eclipse-temurin:21\n"
+	got := detector.Extract(SourceFile{Path: "Dockerfile", Content: []byte(content)})
+	if want := []string{"node:20-alpine", "eclipse-temurin:21"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract got %q, want %q", got, want)
+	}
+	for _, tc := range []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"node:20-alpine", "Node", true},
+		{"docker.io/eclipse-temurin:21", "Java 21", true},
+		{"python:3", "python:3", false},
+	} {
+		if got, ok := detector.Normalize(tc.value); got != tc.want || ok != tc.ok {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
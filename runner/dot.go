@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// chart is selection of graph shared by renderers other than mermaid flowchart.
+// Every node is placed in first group containing it, orphans have their own cluster.
+type chart struct {
+	edges     []Edge
+	clusters  map[string][]string
+	orphans   []string
+	ungrouped []string
+	resources map[string]Resource
+}
+
+// buildChart applies the same options as GenerateFlowchart.
+func buildChart(resources []Resource, tag string, direction string, exclude []string, groups map[string][]string, renderOrphans bool,
+	validTags []string) chart {
+	edges := []Edge{}
+	nodes := []string{}
+	connected := map[string]bool{}
+
+	for _, resource := range resources {
+		source := resource.Tag
+		if !visible(source, exclude, validTags) {
+			continue
+		}
+		if _, grouped := groupOf(source, groups); grouped && len(tag) == 0 {
+			nodes = append(nodes, source)
+		}
+		for _, dep := range sortedKeys(resource.References) {
+			if !visible(dep, exclude, validTags) {
+				continue
+			}
+			connected[source] = true
+			connected[dep] = true
+			if matchesDirection(tag, direction, source, dep) {
+				edges = append(edges, Edge{source, dep})
+				nodes = append(nodes, source, dep)
+			}
+		}
+	}
+
+	orphans := []string{}
+	for _, resource := range resources {
+		if !connected[resource.Tag] && !slices.Contains(exclude, resource.Tag) {
+			fmt.Printf("Orphan found: %s\n", resource.Tag)
+			if _, grouped := groupOf(resource.Tag, groups); renderOrphans && !grouped {
+				orphans = append(orphans, resource.Tag)
+			}
+		}
+	}
+
+	c := chart{
+		edges:     unique(edges),
+		clusters:  map[string][]string{},
+		orphans:   orphans,
+		ungrouped: []string{},
+		resources: resourcesByTag(resources),
+	}
+	for _, node := range unique(nodes) {
+		if group, ok := groupOf(node, groups); ok {
+			c.clusters[group] = append(c.clusters[group], node)
+		} else if !slices.Contains(orphans, node) {
+			c.ungrouped = append(c.ungrouped, node)
+		}
+	}
+	return c
+}
+
+// GenerateDot renders Graphviz digraph with the same options as GenerateFlowchart.
+func GenerateDot(resources []Resource, tag string, direction string, exclude []string, groups map[string][]string, renderOrphans bool,
+	validTags []string, tmap map[string]string) string {
+	c := buildChart(resources, tag, direction, exclude, groups, renderOrphans, validTags)
+
+	dot := "digraph references {\n"
+	dot += "\tnode [shape=box, style=rounded];\n"
+	for i, group := range sortedKeys(c.clusters) {
+		dot += fmt.Sprintf("\tsubgraph cluster_%d {\n", i)
+		dot += fmt.Sprintf("\t\tlabel=%s;\n", dotQuote(group))
+		for _, node := range c.clusters[group] {
+			dot += "\t\t" + dotNode(node, tmap)
+		}
+		dot += "\t}\n"
+	}
+	if len(c.orphans) > 0 {
+		dot += "\tsubgraph cluster_orphans {\n"
+		dot += "\t\tlabel=\"Orphans\";\n"
+		for _, node := range c.orphans {
+			dot += "\t\t" + dotNode(node, tmap)
+		}
+		dot += "\t}\n"
+	}
+	for _, node := range c.ungrouped {
+		dot += "\t" + dotNode(node, tmap)
+	}
+	for _, edge := range c.edges {
+		dot += fmt.Sprintf("\t%s -> %s;\n", dotQuote(edge.Source), dotQuote(edge.Target))
+	}
+	dot += "}\n"
+	return dot
+}
+
+func dotNode(tag string, tmap map[string]string) string {
+	return fmt.Sprintf("%s [label=%s];\n", dotQuote(tag), dotQuote(plainLabel(label(tag, tmap))))
+}
+
+// plainLabel drops markdown emphasis used in translations for mermaid.
+func plainLabel(label string) string {
+	return strings.TrimSpace(strings.NewReplacer("**", "", "`", "").Replace(label))
+}
+
+func dotQuote(value string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(value) + "\""
+}
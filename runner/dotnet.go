@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+)
+
+// dotnetPackages are tracked NuGet packages by prefix of package id.
+var dotnetPackages = map[string]string{
+	"Microsoft.EntityFrameworkCore": "EF Core",
+	"Microsoft.AspNetCore":          "ASP.NET Core",
+	"Microsoft.Azure.Functions":     "Azure Functions",
+}
+
+type dotnetProject struct {
+	Sdk           string `xml:"Sdk,attr"`
+	PropertyGroup []struct {
+		TargetFramework  string `xml:"TargetFramework"`
+		TargetFrameworks string `xml:"TargetFrameworks"`
+	} `xml:"PropertyGroup"`
+	PackageReference []struct {
+		Include string `xml:"Include,attr"`
+		Version string `xml:"Version,attr"`
+		Element string `xml:"Version"`
+	} `xml:"ItemGroup>PackageReference"`
+}
+
+var dotnetFrameworkReg = regexp.MustCompile(`^(netcoreapp|netstandard|net)([0-9.]+)`)
+
+// dotnetFramework maps target framework moniker to name and version: net8.0 to .NET 8.0, netcoreapp3.1 to .NET Core 3.1,
+// netstandard2.0 to .NET Standard 2.0 and net48 to .NET Framework 4.8.
+func dotnetFramework(moniker string) (string, string) {
+	match := dotnetFrameworkReg.FindStringSubmatch(strings.ToLower(strings.TrimSpace(moniker)))
+	if match == nil {
+		return "", ""
+	}
+	switch version := match[2]; {
+	case match[1] == "netcoreapp":
+		return ".NET Core", version
+	case match[1] == "netstandard":
+		return ".NET Standard", version
+	case !strings.Contains(version, "."):
+		return ".NET Framework", strings.Join(strings.Split(version, ""), ".")
+	default:
+		return ".NET", version
+	}
+}
+
+// dotnetDetector reports target frameworks and tracked packages of SDK style project files.
+// Web SDK projects are reported as ASP.NET Core of target framework version.
+type dotnetDetector struct {
+	passthroughNormalize
+}
+
+func (dotnetDetector) Name() string {
+	return "dotnet"
+}
+
+func (dotnetDetector) Matches(path string) bool {
+	return matchesAny([]string{"*.csproj", "*.fsproj", "*.vbproj"}, path)
+}
+
+func (dotnetDetector) Extract(file SourceFile) []string {
+	var project dotnetProject
+	if err := xml.Unmarshal(file.Content, &project); err != nil {
+		return []string{}
+	}
+	values := []string{}
+	for _, group := range project.PropertyGroup {
+		for _, moniker := range strings.Split(group.TargetFramework+";"+group.TargetFrameworks, ";") {
+			name, version := dotnetFramework(moniker)
+			if len(name) == 0 {
+				continue
+			}
+			values = append(values, softwareLabel(name, version))
+			if project.Sdk == "Microsoft.NET.Sdk.Web" && (name == ".NET" || name == ".NET Core") {
+				values = append(values, softwareLabel("ASP.NET Core", version))
+			}
+		}
+	}
+	for _, reference := range project.PackageReference {
+		for prefix, label := range dotnetPackages {
+			if reference.Include == prefix || strings.HasPrefix(reference.Include, prefix+".") {
+				values = append(values, softwareLabel(label, reference.Version+reference.Element))
+			}
+		}
+	}
+	return values
+}
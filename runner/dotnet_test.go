@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDotnetFramework(t *testing.T) {
+	for _, tc := range []struct{ moniker, name, version string }{
+		{"net8.0", ".NET", "8.0"},
+		{"net6.0-windows", ".NET", "6.0"},
+		{"netcoreapp3.1", ".NET Core", "3.1"},
+		{"netstandard2.0", ".NET Standard", "2.0"},
+		{"net48", ".NET Framework", "4.8"},
+		{"net462", ".NET Framework", "4.6.2"},
+		{"uap10.0", "", ""},
+	} {
+		if name, version := dotnetFramework(tc.moniker); name != tc.name || version != tc.version {
+			t.Errorf("dotnetFramework(%q) = %q %q, want %q %q", tc.moniker, name, version, tc.name, tc.version)
+		}
+	}
+}
+
+func TestDotnetDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name: "web project",
+			file: "src/Api/Api.csproj",
+			content: `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Microsoft.EntityFrameworkCore.SqlServer" Version="8.0.1" />
+    <PackageReference Include="Serilog" Version="3.1.1" />
+  </ItemGroup>
+</Project>`,
+			want: []string{".NET 8.0", "ASP.NET Core 8.0", "EF Core 8.0.1"},
+		},
+		{
+			name: "multiple targets",
+			file: "Lib.fsproj",
+			content: `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup><TargetFrameworks>netstandard2.0;net48</TargetFrameworks></PropertyGroup>
+  <ItemGroup><PackageReference Include="Microsoft.Azure.Functions.Worker"><Version>1.20.0</Version></PackageReference></ItemGroup>
+</Project>`,
+			want: []string{".NET Standard 2.0", ".NET Framework 4.8", "Azure Functions 1.20.0"},
+		},
+		{"invalid xml", "App.csproj", "<Project", []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := extract(t, dotnetDetector{}, map[string]string{tc.file: tc.content}, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package runner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SourceGithub  = "github"
+	SourceGit     = "git"
+	SourceLocal   = "local"
+	SourceArchive = "archive"
+)
+
+func fetchRepo(ctx context.Context, repo Repository, executionConfig ExecutionConfig) (string, error) {
+	switch repo.Type {
+	case "", SourceGithub:
+		return fetchClone(ctx, repo, executionConfig, "gh", "repo", "clone", repo.Url)
+	case SourceGit:
+		return fetchClone(ctx, repo, executionConfig, "git", "clone", repo.Url)
+	case SourceLocal:
+		return fetchLocal(repo, executionConfig)
+	case SourceArchive:
+		return fetchArchive(ctx, repo, executionConfig)
+	default:
+		return "", fmt.Errorf("unknown source type %s", repo.Type)
+	}
+}
+
+func fetchClone(ctx context.Context, repo Repository, executionConfig ExecutionConfig, command string, args ...string) (string, error) {
+	if len(executionConfig.WorkDir) == 0 {
+		return "", fmt.Errorf("work dir is required for %s source", command)
+	}
+	path := fmt.Sprintf("%s/%s", executionConfig.WorkDir, repo.Name)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		executionConfig.emit(Event{Type: EventFetching, Repository: repo.Name, Message: fmt.Sprintf("Fetching repo %s", repo.Name)})
+		cmd := exec.CommandContext(ctx, command, append(args, path)...)
+		if err := run(cmd); err != nil {
+			os.RemoveAll(path)
+			return "", fmt.Errorf("%s clone failed: %w", command, err)
+		}
+	}
+	if ref := repoRef(repo, executionConfig); len(ref) > 0 {
+		if err := checkoutRef(ctx, repo, path, ref, executionConfig); err != nil {
+			return "", err
+		}
+	} else if err := checkoutDefaultBranch(ctx, path); err != nil {
+		return "", err
+	} else if executionConfig.Sync {
+		executionConfig.emit(Event{Type: EventFetching, Repository: repo.Name, Message: fmt.Sprintf("Syncing repo %s", repo.Name)})
+		cmd := exec.CommandContext(ctx, "git", "pull")
+		cmd.Dir = path
+		if err := run(cmd); err != nil {
+			executionConfig.emit(Event{Type: EventWarning, Repository: repo.Name, Message: fmt.Sprintf("Failed syncing repo, %s", repo.Name), Err: err})
+		}
+	}
+	return path, nil
+}
+
+// run executes command, including its stderr in returned error.
+func run(cmd *exec.Cmd) error {
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); len(message) > 0 {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
+
+// repoRef returns branch, tag or commit to scan: repository's own or global default.
+func repoRef(repo Repository, executionConfig ExecutionConfig) string {
+	if len(repo.Ref) > 0 {
+		return repo.Ref
+	}
+	return executionConfig.Ref
+}
+
+// checkoutRef detaches HEAD at ref, fetching it from origin when missing locally or when syncing.
+func checkoutRef(ctx context.Context, repo Repository, path string, ref string, executionConfig ExecutionConfig) error {
+	target := ref
+	if executionConfig.Sync || !hasRef(path, ref) {
+		executionConfig.emit(Event{Type: EventFetching, Repository: repo.Name, Message: fmt.Sprintf("Fetching ref %s of repo %s", ref, repo.Name)})
+		fetch := exec.CommandContext(ctx, "git", "fetch", "--quiet", "origin", ref)
+		fetch.Dir = path
+		if err := run(fetch); err == nil {
+			target = "FETCH_HEAD"
+		} else if !hasRef(path, ref) {
+			return fmt.Errorf("fetching ref %s failed: %w", ref, err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "checkout", "--quiet", "--detach", target)
+	cmd.Dir = path
+	if err := run(cmd); err != nil {
+		return fmt.Errorf("checking out ref %s failed: %w", ref, err)
+	}
+	return nil
+}
+
+// checkoutDefaultBranch attaches HEAD left detached by previously configured ref to remote default branch.
+func checkoutDefaultBranch(ctx context.Context, path string) error {
+	attached := exec.CommandContext(ctx, "git", "symbolic-ref", "--quiet", "HEAD")
+	attached.Dir = path
+	if attached.Run() == nil {
+		return nil
+	}
+
+	resolve := func() (string, error) {
+		cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+		cmd.Dir = path
+		out, err := cmd.Output()
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"), err
+	}
+	branch, err := resolve()
+	if err != nil {
+		setHead := exec.CommandContext(ctx, "git", "remote", "set-head", "origin", "--auto")
+		setHead.Dir = path
+		if err := run(setHead); err != nil {
+			return fmt.Errorf("resolving default branch failed: %w", err)
+		}
+		if branch, err = resolve(); err != nil {
+			return fmt.Errorf("resolving default branch failed: %w", err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "checkout", "--quiet", branch)
+	cmd.Dir = path
+	if err := run(cmd); err != nil {
+		return fmt.Errorf("checking out default branch %s failed: %w", branch, err)
+	}
+	return nil
+}
+
+func hasRef(path string, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = path
+	return cmd.Run() == nil
+}
+
+// fetchLocal returns directory scanned in place, it is never modified.
+func fetchLocal(repo Repository, executionConfig ExecutionConfig) (string, error) {
+	path := localPath(repo)
+	if len(repo.Ref) > 0 {
+		executionConfig.emit(Event{Type: EventWarning, Repository: repo.Name, Message: fmt.Sprintf("Ref %s ignored for local repo %s, it is scanned as is", repo.Ref, repo.Name)})
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", path)
+	}
+	return path, nil
+}
+
+func localPath(repo Repository) string {
+	if len(repo.Path) > 0 {
+		return repo.Path
+	}
+	return strings.TrimPrefix(repo.Url, "file://")
+}
+
+// fetchArchive extracts tarball or zip into work dir. Archive having single top level directory is scanned from there.
+func fetchArchive(ctx context.Context, repo Repository, executionConfig ExecutionConfig) (string, error) {
+	if len(executionConfig.WorkDir) == 0 {
+		return "", fmt.Errorf("work dir is required for archive source")
+	}
+	path := fmt.Sprintf("%s/%s", executionConfig.WorkDir, repo.Name)
+
+	if len(repo.Ref) > 0 {
+		executionConfig.emit(Event{Type: EventWarning, Repository: repo.Name, Message: fmt.Sprintf("Ref %s ignored for archive %s", repo.Ref, repo.Name)})
+	}
+	if executionConfig.Sync {
+		os.RemoveAll(path)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		executionConfig.emit(Event{Type: EventFetching, Repository: repo.Name, Message: fmt.Sprintf("Fetching archive %s", repo.Name)})
+		if err := extractArchive(ctx, repo, path); err != nil {
+			os.RemoveAll(path)
+			return "", err
+		}
+	}
+
+	entries, err := os.ReadDir(path)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(path, entries[0].Name()), nil
+	}
+	return path, nil
+}
+
+func extractArchive(ctx context.Context, repo Repository, destination string) error {
+	location := localPath(repo)
+	name := strings.ToLower(location)
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		downloaded, err := download(ctx, location)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(downloaded)
+		location = downloaded
+	}
+
+	if err := os.MkdirAll(destination, os.ModePerm); err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return extractZip(location, destination)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return extractTar(location, destination, true)
+	case strings.HasSuffix(name, ".tar"):
+		return extractTar(location, destination, false)
+	default:
+		return fmt.Errorf("unsupported archive %s", name)
+	}
+}
+
+func download(ctx context.Context, url string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download of %s failed with status %s", url, response.Status)
+	}
+
+	file, err := os.CreateTemp("", "reference-finder-*")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, response.Body); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// archiveTarget resolves entry name inside destination, rejecting entries escaping it.
+func archiveTarget(destination string, name string) (string, error) {
+	target := filepath.Join(destination, name)
+	if target != filepath.Clean(destination) && !strings.HasPrefix(target, filepath.Clean(destination)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return target, nil
+}
+
+func writeArchiveFile(target string, reader io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, reader)
+	return err
+}
+
+func extractZip(archive string, destination string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for _, f := range reader.File {
+		target, err := archiveTarget(destination, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		content, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeArchiveFile(target, content, f.Mode())
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTar(archive string, destination string, gzipped bool) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var stream io.Reader = file
+	if gzipped {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := archiveTarget(destination, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, reader, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func revParse(t *testing.T, dir string, ref string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"rev-parse"}, strings.Fields(ref)...)...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse %s: %s", ref, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestFetchCloneRefs(t *testing.T) {
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	writeFiles(t, origin, map[string]string{"main.go": "v1"})
+	git(t, origin, "init", "-q", "-b", "trunk")
+	git(t, origin, "add", ".")
+	git(t, origin, "commit", "-qm", "first")
+	git(t, origin, "tag", "v1")
+	writeFiles(t, origin, map[string]string{"main.go": "v2"})
+	git(t, origin, "commit", "-qam", "second")
+
+	executionConfig := ExecutionConfig{WorkDir: filepath.Join(root, "workdir")}
+	repo := Repository{Name: "repo", Type: SourceGit, Url: origin}
+	clone := filepath.Join(executionConfig.WorkDir, "repo")
+	fetch := func(ref string, sync bool) {
+		t.Helper()
+		repo.Ref = ref
+		executionConfig.Sync = sync
+		if _, err := fetchRepo(context.Background(), repo, executionConfig); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fetch("v1", false)
+	if got, want := revParse(t, clone, "HEAD"), revParse(t, origin, "v1"); got != want {
+		t.Errorf("pinned to tag: HEAD %s, want %s", got, want)
+	}
+
+	git(t, origin, "checkout", "-qb", "feature")
+	writeFiles(t, origin, map[string]string{"feature.go": ""})
+	git(t, origin, "add", ".")
+	git(t, origin, "commit", "-qm", "feature")
+	fetch("feature", false)
+	if got, want := revParse(t, clone, "HEAD"), revParse(t, origin, "feature"); got != want {
+		t.Errorf("branch missing locally is fetched: HEAD %s, want %s", got, want)
+	}
+
+	writeFiles(t, origin, map[string]string{"feature.go": "changed"})
+	git(t, origin, "commit", "-qam", "feature moved")
+	fetch("v1", false)
+	fetch("feature", true)
+	if got, want := revParse(t, clone, "HEAD"), revParse(t, origin, "feature"); got != want {
+		t.Errorf("sync fetches ref again: HEAD %s, want %s", got, want)
+	}
+
+	git(t, origin, "checkout", "-q", "trunk")
+	writeFiles(t, origin, map[string]string{"main.go": "v3"})
+	git(t, origin, "commit", "-qam", "third")
+	fetch("", true)
+	if got, want := revParse(t, clone, "HEAD"), revParse(t, origin, "trunk"); got != want {
+		t.Errorf("without ref default branch is pulled: HEAD %s, want %s", got, want)
+	}
+	if got := revParse(t, clone, "--symbolic-full-name HEAD"); got != "refs/heads/trunk" {
+		t.Errorf("HEAD is %s, want attached to trunk", got)
+	}
+
+	repo.Ref = "missing"
+	if _, err := fetchRepo(context.Background(), repo, executionConfig); err == nil {
+		t.Error("expected error for missing ref")
+	}
+}
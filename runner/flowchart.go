@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-func GenerateFlowchart(resources []Resource, tag string, exclude []string, groups map[string][]string, renderOrphans bool,
+func GenerateFlowchart(resources []Resource, tag string, direction string, exclude []string, groups map[string][]string, renderOrphans bool,
 	validTags []string, tmap map[string]string) string {
 	flowchart := "flowchart TD\n"
 
@@ -45,7 +45,7 @@ func GenerateFlowchart(resources []Resource, tag string, exclude []string, group
 			visited[source] = true
 			visited[dep] = true
 
-			if len(tag) == 0 || (len(tag) > 0 && (source == tag || dep == tag)) {
+			if matchesDirection(tag, direction, source, dep) {
 				added := false
 				for groupName, group := range groups {
 					if slices.Contains(group, dep) {
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// goModules are tracked modules by path without major version suffix.
+var goModules = map[string]string{
+	"github.com/gin-gonic/gin":  "Gin",
+	"github.com/labstack/echo":  "Echo",
+	"github.com/gofiber/fiber":  "Fiber",
+	"github.com/go-chi/chi":     "Chi",
+	"github.com/gorilla/mux":    "Gorilla mux",
+	"google.golang.org/grpc":    "gRPC",
+	"github.com/spf13/cobra":    "Cobra",
+	"github.com/aws/aws-cdk-go": "Aws-cdk",
+}
+
+var goMajorSuffixReg = regexp.MustCompile(`/v[0-9]+$`)
+
+// goModDetector reports go directive and tracked required modules of go.mod.
+type goModDetector struct {
+	passthroughNormalize
+}
+
+func (goModDetector) Name() string {
+	return "go"
+}
+
+func (goModDetector) Matches(path string) bool {
+	return matchesAny([]string{"go.mod"}, path)
+}
+
+func (goModDetector) Extract(file SourceFile) []string {
+	values := []string{}
+	inRequire := false
+	for _, line := range strings.Split(string(file.Content), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require":
+			fields = fields[1:]
+		case fields[0] == "go" && len(fields) > 1:
+			values = append(values, softwareLabel("Go", fields[1]))
+			continue
+		case !inRequire:
+			continue
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		if label, tracked := goModules[goMajorSuffixReg.ReplaceAllString(fields[0], "")]; tracked {
+			values = append(values, softwareLabel(label, strings.TrimPrefix(fields[1], "v")))
+		}
+	}
+	return values
+}
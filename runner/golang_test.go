@@ -0,0 +1,25 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGoModDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"require block", "module example.com/app\n\ngo 1.21.3\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgithub.com/labstack/echo/v4 v4.11.4 // indirect\n\tgolang.org/x/net v0.20.0\n)\n", []string{"Go 1.21.3", "Gin 1.9.1", "Echo 4.11.4"}},
+		{"single require", "module example.com/app\ngo 1.22\nrequire github.com/spf13/cobra v1.8.0\n", []string{"Go 1.22", "Cobra 1.8.0"}},
+		{"replace ignored", "module example.com/app\nreplace github.com/gin-gonic/gin => ../gin\n", []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := extract(t, goModDetector{}, map[string]string{"go.mod": tc.content}, "go.mod")
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
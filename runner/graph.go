@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"slices"
+)
+
+// visible applies exclude list and valid tags (when given) to tag.
+func visible(tag string, exclude []string, validTags []string) bool {
+	if slices.Contains(exclude, tag) {
+		return false
+	}
+	return len(validTags) == 0 || slices.Contains(validTags, tag)
+}
+
+// groupOf returns first group, in name order, containing tag.
+func groupOf(tag string, groups map[string][]string) (string, bool) {
+	for _, name := range sortedKeys(groups) {
+		if slices.Contains(groups[name], tag) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// label returns display name of tag from translation mapping.
+func label(tag string, tmap map[string]string) string {
+	if v, ok := tmap[tag]; ok {
+		return v
+	}
+	return tag
+}
+
+// Directions of walking graph from resource: resources it depends on (downstream), resources using it (upstream) or both.
+const (
+	DirectionBoth       = "both"
+	DirectionUpstream   = "upstream"
+	DirectionDownstream = "downstream"
+)
+
+// matchesDirection tells whether edge should be drawn in chart of tag, any edge matches when tag is empty.
+func matchesDirection(tag string, direction string, source string, dep string) bool {
+	switch {
+	case len(tag) == 0:
+		return true
+	case direction == DirectionUpstream:
+		return dep == tag
+	case direction == DirectionDownstream:
+		return source == tag
+	default:
+		return source == tag || dep == tag
+	}
+}
+
+// ComputeUsedBy fills UsedBy of every resource with sorted tags of resources referencing it.
+func ComputeUsedBy(resources []Resource) []Resource {
+	incoming := IncomingReferences(resources)
+	for i, resource := range resources {
+		resources[i].UsedBy = nil
+		if sources, ok := incoming[resource.Tag]; ok {
+			resources[i].UsedBy = sortedKeys(sources)
+		}
+	}
+	return resources
+}
+
+// IncomingReferences indexes references by referenced tag and then by referencing resource.
+func IncomingReferences(resources []Resource) map[string]map[string][]Reference {
+	incoming := map[string]map[string][]Reference{}
+	for _, resource := range resources {
+		for target, refs := range resource.References {
+			if incoming[target] == nil {
+				incoming[target] = map[string][]Reference{}
+			}
+			incoming[target][resource.Tag] = append(incoming[target][resource.Tag], refs...)
+		}
+	}
+	return incoming
+}
+
+// adjacency returns sorted neighbours of every visible tag following edges in direction.
+func adjacency(resources []Resource, direction string, exclude []string, validTags []string) map[string][]string {
+	neighbours := map[string][]string{}
+	for _, resource := range resources {
+		source := resource.Tag
+		if !visible(source, exclude, validTags) {
+			continue
+		}
+		for dep := range resource.References {
+			if !visible(dep, exclude, validTags) {
+				continue
+			}
+			if direction != DirectionUpstream {
+				neighbours[source] = append(neighbours[source], dep)
+			}
+			if direction != DirectionDownstream {
+				neighbours[dep] = append(neighbours[dep], source)
+			}
+		}
+	}
+	for tag := range neighbours {
+		neighbours[tag] = unique(neighbours[tag])
+		slices.Sort(neighbours[tag])
+	}
+	return neighbours
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	_ "embed"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+//go:embed viewer.html
+var viewerTemplate string
+
+type viewerNode struct {
+	Id       string   `json:"id"`
+	Label    string   `json:"label"`
+	Groups   []string `json:"groups"`
+	Software []string `json:"software"`
+}
+
+type viewerEdge struct {
+	Source     string      `json:"source"`
+	Target     string      `json:"target"`
+	Kinds      []string    `json:"kinds"`
+	References []Reference `json:"references"`
+}
+
+type viewerData struct {
+	Nodes  []viewerNode `json:"nodes"`
+	Edges  []viewerEdge `json:"edges"`
+	Groups []string     `json:"groups"`
+}
+
+// GenerateHtml renders offline page with interactive graph, data is embedded and nothing is loaded from network.
+func GenerateHtml(resources []Resource, exclude []string, groups map[string][]string, validTags []string, tmap map[string]string) string {
+	data := viewerData{
+		Nodes:  []viewerNode{},
+		Edges:  []viewerEdge{},
+		Groups: sortedKeys(groups),
+	}
+
+	tags := []string{}
+	for _, resource := range resources {
+		if !visible(resource.Tag, exclude, validTags) {
+			continue
+		}
+		tags = append(tags, resource.Tag)
+		for _, dep := range sortedKeys(resource.References) {
+			if !visible(dep, exclude, validTags) {
+				continue
+			}
+			tags = append(tags, dep)
+			kinds := resource.Kinds(dep)
+			if kinds == nil {
+				kinds = []string{}
+			}
+			data.Edges = append(data.Edges, viewerEdge{
+				Source:     resource.Tag,
+				Target:     dep,
+				Kinds:      kinds,
+				References: resource.References[dep],
+			})
+		}
+	}
+
+	byTag := resourcesByTag(resources)
+	for _, tag := range unique(tags) {
+		memberOf := []string{}
+		for _, group := range data.Groups {
+			if slices.Contains(groups[group], tag) {
+				memberOf = append(memberOf, group)
+			}
+		}
+		software := byTag[tag].Software
+		if software == nil {
+			software = []string{}
+		}
+		data.Nodes = append(data.Nodes, viewerNode{
+			Id:       tag,
+			Label:    plainLabel(label(tag, tmap)),
+			Groups:   memberOf,
+			Software: software,
+		})
+	}
+
+	// json.Marshal escapes <, > and & so data cannot close the script element
+	encoded, _ := json.Marshal(data)
+	return strings.Replace(viewerTemplate, "/*DATA*/null", string(encoded), 1)
+}
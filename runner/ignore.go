@@ -0,0 +1,299 @@
+package runner
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const defaultMaxFileSize = 1024 * 1024
+
+// Reasons for skipping files and directories during walk, used as keys of Findings.Skipped.
+const (
+	SkipGitignore = "gitignore"
+	SkipExcluded  = "excluded"
+	SkipBinary    = "binary"
+	SkipSize      = "size"
+)
+
+// skippedPaths records what walk left out: files with their reason and skipped directories with number of files under them.
+// Paths are relative to repository root.
+type skippedPaths struct {
+	Files map[string]string     `json:"files,omitempty"`
+	Dirs  map[string]skippedDir `json:"dirs,omitempty"`
+}
+
+type skippedDir struct {
+	Reason string `json:"reason"`
+	Files  int    `json:"files"`
+}
+
+func newSkippedPaths() skippedPaths {
+	return skippedPaths{Files: map[string]string{}, Dirs: map[string]skippedDir{}}
+}
+
+func (skipped skippedPaths) clone() skippedPaths {
+	cloned := newSkippedPaths()
+	for path, reason := range skipped.Files {
+		cloned.Files[path] = reason
+	}
+	for path, dir := range skipped.Dirs {
+		cloned.Dirs[path] = dir
+	}
+	return cloned
+}
+
+// counts returns number of skipped files by reason, files under skipped directories included.
+func (skipped skippedPaths) counts() map[string]int {
+	counts := map[string]int{}
+	for _, reason := range skipped.Files {
+		counts[reason]++
+	}
+	for _, dir := range skipped.Dirs {
+		counts[dir.Reason] += dir.Files
+	}
+	return counts
+}
+
+// dirOf returns skipped directory containing path or empty string.
+func (skipped skippedPaths) dirOf(path string) string {
+	for dir := range skipped.Dirs {
+		if strings.HasPrefix(path, dir+"/") {
+			return dir
+		}
+	}
+	return ""
+}
+
+// countFiles returns number of files under directory, git metadata excluded.
+func countFiles(dir string) int {
+	count := 0
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		count++
+		return nil
+	})
+	return count
+}
+
+type gitignorePattern struct {
+	glob     string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreRules decides which files of single repository are scanned.
+// .gitignore files are loaded lazily for every visited directory.
+type ignoreRules struct {
+	root        string
+	include     []string
+	exclude     []string
+	maxFileSize int64
+	gitignores  map[string][]gitignorePattern
+}
+
+func newIgnoreRules(root string, executionConfig ExecutionConfig) *ignoreRules {
+	maxFileSize := executionConfig.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = defaultMaxFileSize
+	}
+	return &ignoreRules{
+		root:        root,
+		include:     executionConfig.Include,
+		exclude:     executionConfig.Exclude,
+		maxFileSize: maxFileSize,
+		gitignores:  map[string][]gitignorePattern{},
+	}
+}
+
+// skip returns reason why path should not be scanned or empty string when it should.
+func (rules *ignoreRules) skip(file string, info os.FileInfo) string {
+	rel, err := filepath.Rel(rules.root, file)
+	if err != nil || rel == "." {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+
+	if rules.gitignored(rel, info.IsDir()) {
+		return SkipGitignore
+	}
+	if matchesAny(rules.exclude, rel) {
+		return SkipExcluded
+	}
+	if info.IsDir() {
+		return ""
+	}
+	if len(rules.include) > 0 && !matchesAny(rules.include, rel) {
+		return SkipExcluded
+	}
+	if rules.maxFileSize > 0 && info.Size() > rules.maxFileSize {
+		return SkipSize
+	}
+	if isBinary(file) {
+		return SkipBinary
+	}
+	return ""
+}
+
+// skipWithParents checks file and all its directories up to repository root, used when file is not reached by walk.
+// Reason is returned together with skipped path, which is either file or one of its directories.
+func (rules *ignoreRules) skipWithParents(file string, info os.FileInfo) (string, string) {
+	rel, err := filepath.Rel(rules.root, file)
+	if err != nil {
+		return "", ""
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i := 1; i < len(segments); i++ {
+		dir := filepath.Join(rules.root, filepath.FromSlash(strings.Join(segments[:i], "/")))
+		dirInfo, err := os.Stat(dir)
+		if err != nil {
+			return "", ""
+		}
+		if reason := rules.skip(dir, dirInfo); len(reason) > 0 {
+			return reason, dir
+		}
+	}
+	return rules.skip(file, info), file
+}
+
+// gitignored applies .gitignore files from repository root down to directory of path, last matching pattern wins.
+func (rules *ignoreRules) gitignored(rel string, isDir bool) bool {
+	ignored := false
+	segments := strings.Split(rel, "/")
+	for i := 0; i < len(segments); i++ {
+		dir := strings.Join(segments[:i], "/")
+		sub := strings.Join(segments[i:], "/")
+		for _, p := range rules.loadGitignore(dir) {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			var matched bool
+			if p.anchored {
+				matched = matchGlob(p.glob, sub)
+			} else {
+				matched = matchGlob(p.glob, segments[len(segments)-1])
+			}
+			if matched {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func (rules *ignoreRules) loadGitignore(dir string) []gitignorePattern {
+	patterns, ok := rules.gitignores[dir]
+	if ok {
+		return patterns
+	}
+	patterns = readGitignore(filepath.Join(rules.root, filepath.FromSlash(dir), ".gitignore"))
+	rules.gitignores[dir] = patterns
+	return patterns
+}
+
+func readGitignore(file string) []gitignorePattern {
+	patterns := []gitignorePattern{}
+	f, err := os.Open(file)
+	if err != nil {
+		return patterns
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := gitignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, "\\") {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if len(line) == 0 {
+			continue
+		}
+		p.glob = line
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// matchesAny checks configured globs against repository relative path. Globs without slash match file name at any depth.
+func matchesAny(globs []string, rel string) bool {
+	name := path.Base(rel)
+	for _, glob := range globs {
+		if matchGlob(glob, rel) || (!strings.Contains(glob, "/") && matchGlob(glob, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches slash separated path against glob where `**` stands for any number of directories.
+func matchGlob(glob string, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(glob []string, name []string) bool {
+	if len(glob) == 0 {
+		return len(name) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(glob[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	matched, _ := path.Match(glob[0], name[0])
+	return matched && matchSegments(glob[1:], name[1:])
+}
+
+// isBinary sniffs beginning of file for NUL byte, same heuristic as git uses.
+func isBinary(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buffer := make([]byte, 8000)
+	n, err := io.ReadFull(f, buffer)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false
+	}
+	for _, b := range buffer[:n] {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
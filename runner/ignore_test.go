@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	for _, tc := range []struct {
+		glob string
+		name string
+		want bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/runner/main.go", true},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/**", "docs", true},
+		{"docs/**", "src/docs/a.md", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"**", "anything/at/all", true},
+		{"file?.txt", "file1.txt", true},
+		{"[ab].txt", "c.txt", false},
+		{"[", "[", false},
+	} {
+		if got := matchGlob(tc.glob, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.glob, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	for _, tc := range []struct {
+		globs []string
+		rel   string
+		want  bool
+	}{
+		{[]string{"*.min.js"}, "web/dist/app.min.js", true},
+		{[]string{"web/*.js"}, "web/dist/app.js", false},
+		{[]string{"web/**/*.js"}, "web/dist/app.js", true},
+		{[]string{"vendor"}, "pkg/vendor", true},
+		{[]string{}, "main.go", false},
+		{[]string{"*.md", "*.go"}, "cmd/main.go", true},
+	} {
+		if got := matchesAny(tc.globs, tc.rel); got != tc.want {
+			t.Errorf("matchesAny(%q, %q) = %v, want %v", tc.globs, tc.rel, got, tc.want)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadGitignore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".gitignore")
+	writeFiles(t, filepath.Dir(file), map[string]string{".gitignore": "# comment\n\n*.log  \n!keep.log\n\\#hash\nbuild/\n/root.txt\ndocs/*.md\n/\n"})
+	want := []gitignorePattern{
+		{glob: "*.log"},
+		{glob: "keep.log", negate: true},
+		{glob: "#hash"},
+		{glob: "build", dirOnly: true},
+		{glob: "root.txt", anchored: true},
+		{glob: "docs/*.md", anchored: true},
+	}
+	got := readGitignore(file)
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pattern %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := readGitignore(filepath.Join(t.TempDir(), "missing")); len(got) != 0 {
+		t.Errorf("missing file: got %+v", got)
+	}
+}
+
+func TestIgnoreRulesSkip(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":          "*.log\n!keep.log\nbuild/\n/root.txt\n",
+		"app.log":             "",
+		"keep.log":            "",
+		"root.txt":            "",
+		"build/out.js":        "",
+		"src/build":           "file named build",
+		"src/root.txt":        "",
+		"src/.gitignore":      "!app.log\nlocal.txt\n",
+		"src/app.log":         "",
+		"src/local.txt":       "",
+		"src/main.go":         "package main",
+		"src/main_test.go":    "package main",
+		"vendor/lib.go":       "",
+		"big.txt":             "0123456789",
+		"image.png":           "PNG\x00\x01",
+		"other/src/local.txt": "",
+	})
+	executionConfig := ExecutionConfig{}
+	executionConfig.Exclude = []string{"vendor", "*_test.go"}
+	executionConfig.MaxFileSize = 9
+	rules := newIgnoreRules(root, executionConfig)
+	for _, tc := range []struct {
+		rel  string
+		want string
+	}{
+		{"app.log", SkipGitignore},
+		{"root.txt", SkipGitignore},
+		{"src/root.txt", ""},
+		{"build", SkipGitignore},
+		{"src/build", SkipSize},
+		{"src/app.log", ""},
+		{"src/local.txt", SkipGitignore},
+		{"other/src/local.txt", ""},
+		{"src/main.go", SkipSize},
+		{"src/main_test.go", SkipExcluded},
+		{"vendor", SkipExcluded},
+		{"big.txt", SkipSize},
+		{"image.png", SkipBinary},
+		{"keep.log", ""},
+	} {
+		file := filepath.Join(root, filepath.FromSlash(tc.rel))
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules.skip(file, info); got != tc.want {
+			t.Errorf("skip(%q) = %q, want %q", tc.rel, got, tc.want)
+		}
+	}
+
+	file := filepath.Join(root, "build", "out.js")
+	info, _ := os.Stat(file)
+	if reason, at := rules.skipWithParents(file, info); reason != SkipGitignore || at != filepath.Join(root, "build") {
+		t.Errorf("skipWithParents(build/out.js) = %q, %q, want %q, build", reason, at, SkipGitignore)
+	}
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Impact is resource reached from analysed tags, Path is the shortest walk starting at one of them.
+type Impact struct {
+	Tag      string   `json:"tag"`
+	Distance int      `json:"distance"`
+	Path     []string `json:"path"`
+}
+
+// AnalyzeImpact walks graph breadth-first from tags. Upstream direction finds resources using tags,
+// downstream resources they depend on. Depth 0 means no limit, tags themselves are not part of result.
+func AnalyzeImpact(resources []Resource, tags []string, direction string, depth int, exclude []string, validTags []string) []Impact {
+	neighbours := adjacency(resources, direction, exclude, validTags)
+
+	previous := map[string]string{}
+	distance := map[string]int{}
+	queue := []string{}
+	for _, tag := range tags {
+		if _, seen := distance[tag]; !seen {
+			distance[tag] = 0
+			queue = append(queue, tag)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if depth > 0 && distance[current] >= depth {
+			continue
+		}
+		for _, next := range neighbours[current] {
+			if _, seen := distance[next]; seen {
+				continue
+			}
+			distance[next] = distance[current] + 1
+			previous[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	impacts := []Impact{}
+	for tag, d := range distance {
+		if d == 0 {
+			continue
+		}
+		path := []string{tag}
+		for step := tag; distance[step] > 0; {
+			step = previous[step]
+			path = append([]string{step}, path...)
+		}
+		impacts = append(impacts, Impact{Tag: tag, Distance: d, Path: path})
+	}
+	slices.SortFunc(impacts, func(a, b Impact) int {
+		if a.Distance != b.Distance {
+			return cmp.Compare(a.Distance, b.Distance)
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return impacts
+}
+
+// ImpactGraph keeps only edges walked by AnalyzeImpact in given direction, references of kept edges are preserved.
+func ImpactGraph(resources []Resource, impacts []Impact, direction string) []Resource {
+	byTag := resourcesByTag(resources)
+	graph := map[string]Resource{}
+	keep := func(source string, target string) {
+		references, ok := byTag[source].References[target]
+		if !ok {
+			return
+		}
+		resource, ok := graph[source]
+		if !ok {
+			resource = Resource{Tag: source, Commit: byTag[source].Commit, References: map[string][]Reference{}}
+		}
+		resource.References[target] = references
+		graph[source] = resource
+	}
+	for _, impact := range impacts {
+		for i := 1; i < len(impact.Path); i++ {
+			from, to := impact.Path[i-1], impact.Path[i]
+			if direction != DirectionUpstream {
+				keep(from, to)
+			}
+			if direction != DirectionDownstream {
+				keep(to, from)
+			}
+		}
+	}
+
+	subgraph := []Resource{}
+	for _, tag := range sortedKeys(graph) {
+		subgraph = append(subgraph, graph[tag])
+	}
+	return subgraph
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// impactFixture: a -> b, a -> c, b -> c, d -> b, e -> c, x -> b.
+func impactFixture() []Resource {
+	ref := func(path string) []Reference { return []Reference{{Path: path}} }
+	return []Resource{
+		{Tag: "a", References: map[string][]Reference{"b": ref("a.go"), "c": ref("a.go")}},
+		{Tag: "b", References: map[string][]Reference{"c": ref("b.go")}},
+		{Tag: "c"},
+		{Tag: "d", References: map[string][]Reference{"b": ref("d.go")}},
+		{Tag: "e", References: map[string][]Reference{"c": ref("e.go")}},
+		{Tag: "x", References: map[string][]Reference{"b": ref("x.go")}},
+	}
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		tags      []string
+		direction string
+		depth     int
+		exclude   []string
+		validTags []string
+		want      []Impact
+	}{
+		{
+			name: "upstream", tags: []string{"c"}, direction: DirectionUpstream,
+			want: []Impact{
+				{Tag: "a", Distance: 1, Path: []string{"c", "a"}},
+				{Tag: "b", Distance: 1, Path: []string{"c", "b"}},
+				{Tag: "e", Distance: 1, Path: []string{"c", "e"}},
+				{Tag: "d", Distance: 2, Path: []string{"c", "b", "d"}},
+				{Tag: "x", Distance: 2, Path: []string{"c", "b", "x"}},
+			},
+		},
+		{
+			name: "upstream with depth and exclude", tags: []string{"b"}, direction: DirectionUpstream, depth: 1, exclude: []string{"x"},
+			want: []Impact{
+				{Tag: "a", Distance: 1, Path: []string{"b", "a"}},
+				{Tag: "d", Distance: 1, Path: []string{"b", "d"}},
+			},
+		},
+		{
+			name: "downstream", tags: []string{"d"}, direction: DirectionDownstream,
+			want: []Impact{
+				{Tag: "b", Distance: 1, Path: []string{"d", "b"}},
+				{Tag: "c", Distance: 2, Path: []string{"d", "b", "c"}},
+			},
+		},
+		{
+			name: "both with valid tags", tags: []string{"e"}, direction: DirectionBoth, validTags: []string{"a", "c", "e"},
+			want: []Impact{
+				{Tag: "c", Distance: 1, Path: []string{"e", "c"}},
+				{Tag: "a", Distance: 2, Path: []string{"e", "c", "a"}},
+			},
+		},
+		{
+			name: "many tags", tags: []string{"d", "x", "d"}, direction: DirectionDownstream, depth: 1,
+			want: []Impact{
+				{Tag: "b", Distance: 1, Path: []string{"d", "b"}},
+			},
+		},
+		{
+			name: "unknown tag", tags: []string{"missing"}, direction: DirectionBoth,
+			want: []Impact{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AnalyzeImpact(impactFixture(), tc.tags, tc.direction, tc.depth, tc.exclude, tc.validTags)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestImpactGraph(t *testing.T) {
+	resources := impactFixture()
+	edges := func(graph []Resource) map[string][]string {
+		result := map[string][]string{}
+		for _, resource := range graph {
+			result[resource.Tag] = sortedKeys(resource.References)
+		}
+		return result
+	}
+
+	upstream := ImpactGraph(resources, AnalyzeImpact(resources, []string{"c"}, DirectionUpstream, 0, nil, nil), DirectionUpstream)
+	// a -> b is not walked, a is reached directly from c.
+	want := map[string][]string{"a": {"c"}, "b": {"c"}, "d": {"b"}, "e": {"c"}, "x": {"b"}}
+	if got := edges(upstream); !reflect.DeepEqual(got, want) {
+		t.Errorf("upstream got %v, want %v", got, want)
+	}
+	if got := upstream[0].References["c"]; !reflect.DeepEqual(got, []Reference{{Path: "a.go"}}) {
+		t.Errorf("references not preserved: %+v", got)
+	}
+
+	downstream := ImpactGraph(resources, AnalyzeImpact(resources, []string{"a"}, DirectionDownstream, 0, nil, nil), DirectionDownstream)
+	want = map[string][]string{"a": {"b", "c"}}
+	if got := edges(downstream); !reflect.DeepEqual(got, want) {
+		t.Errorf("downstream got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type InventoryVersion struct {
+	Version   string   `json:"version"`
+	Resources []string `json:"resources"`
+}
+
+type InventoryTechnology struct {
+	Name     string             `json:"name"`
+	Versions []InventoryVersion `json:"versions"`
+}
+
+// InventoryResource is matrix row, versions of every technology used by resource.
+type InventoryResource struct {
+	Tag      string              `json:"tag"`
+	Software map[string][]string `json:"software"`
+}
+
+// Inventory pivots detected software: resources using every technology version and technologies of every resource.
+type Inventory struct {
+	Technologies []InventoryTechnology `json:"technologies"`
+	Resources    []InventoryResource   `json:"resources"`
+}
+
+// BuildInventory collects software of visible resources, versions are ordered numerically.
+func BuildInventory(resources []Resource, exclude []string, validTags []string) Inventory {
+	users := map[string]map[string][]string{}
+	inventory := Inventory{Technologies: []InventoryTechnology{}, Resources: []InventoryResource{}}
+	for _, resource := range resources {
+		if !visible(resource.Tag, exclude, validTags) || len(resource.Software) == 0 {
+			continue
+		}
+		row := InventoryResource{Tag: resource.Tag, Software: map[string][]string{}}
+		for _, software := range resource.Software {
+			name, version := ParseSoftware(software)
+			if users[name] == nil {
+				users[name] = map[string][]string{}
+			}
+			users[name][version] = append(users[name][version], resource.Tag)
+			row.Software[name] = append(row.Software[name], version)
+		}
+		for name := range row.Software {
+			row.Software[name] = unique(row.Software[name])
+			slices.SortFunc(row.Software[name], compareVersions)
+		}
+		inventory.Resources = append(inventory.Resources, row)
+	}
+	slices.SortFunc(inventory.Resources, func(a, b InventoryResource) int { return cmp.Compare(a.Tag, b.Tag) })
+
+	for _, name := range sortedKeys(users) {
+		technology := InventoryTechnology{Name: name}
+		for version, tags := range users[name] {
+			tags = unique(tags)
+			slices.Sort(tags)
+			technology.Versions = append(technology.Versions, InventoryVersion{Version: version, Resources: tags})
+		}
+		slices.SortFunc(technology.Versions, func(a, b InventoryVersion) int { return compareVersions(a.Version, b.Version) })
+		inventory.Technologies = append(inventory.Technologies, technology)
+	}
+	return inventory
+}
+
+// Markdown renders every technology version with its resources, or resource × technology table when matrix is set.
+func (inv Inventory) Markdown(matrix bool) string {
+	sb := strings.Builder{}
+	if matrix {
+		writeMarkdownTable(&sb, inv.matrixRows())
+		return sb.String()
+	}
+	for _, technology := range inv.Technologies {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", technology.Name))
+		for _, version := range technology.Versions {
+			name := version.Version
+			if len(name) == 0 {
+				name = "unknown version"
+			}
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", name, strings.Join(version.Resources, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// CSV renders one row per technology, version and resource, or resource × technology table when matrix is set.
+func (inv Inventory) CSV(matrix bool) string {
+	rows := [][]string{{"Technology", "Version", "Resource"}}
+	if matrix {
+		rows = inv.matrixRows()
+	} else {
+		for _, technology := range inv.Technologies {
+			for _, version := range technology.Versions {
+				for _, tag := range version.Resources {
+					rows = append(rows, []string{technology.Name, version.Version, tag})
+				}
+			}
+		}
+	}
+	buf := bytes.Buffer{}
+	w := csv.NewWriter(&buf)
+	w.WriteAll(rows)
+	return buf.String()
+}
+
+func (inv Inventory) matrixRows() [][]string {
+	header := []string{"Resource"}
+	for _, technology := range inv.Technologies {
+		header = append(header, technology.Name)
+	}
+	rows := [][]string{header}
+	for _, resource := range inv.Resources {
+		row := []string{resource.Tag}
+		for _, technology := range inv.Technologies {
+			row = append(row, strings.Join(resource.Software[technology.Name], ", "))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// compareVersions compares digit runs numerically and everything else as text, so 8 < 11 < 11.0.2.
+func compareVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		if aErr == nil && bErr == nil {
+			c = cmp.Compare(an, bn)
+		} else {
+			c = cmp.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+func versionParts(version string) []string {
+	parts := []string{}
+	for i := 0; i < len(version); {
+		j := i + 1
+		digit := unicode.IsDigit(rune(version[i]))
+		for j < len(version) && unicode.IsDigit(rune(version[j])) == digit {
+			j++
+		}
+		parts = append(parts, version[i:j])
+		i = j
+	}
+	return parts
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"8", "11", -1},
+		{"11", "11.0.2", -1},
+		{"1.10", "1.9", 1},
+		{"2.7.2", "2.7.2", 0},
+		{"", "1", -1},
+		{"3.1.0-RC1", "3.1.0-RC2", -1},
+		{"jdk-17", "jdk-8", 1},
+	} {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := compareVersions(tc.b, tc.a); got != -tc.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	resources := []Resource{
+		{Tag: "b", Software: []string{"Java 11", "Spring 2.7.2", "Java 8"}},
+		{Tag: "a", Software: []string{"Java 17", "node:21"}},
+		{Tag: "c", Software: []string{"Java 11"}},
+		{Tag: "hidden", Software: []string{"Java 21"}},
+		{Tag: "empty"},
+	}
+
+	got := BuildInventory(resources, []string{"hidden"}, nil)
+
+	want := Inventory{
+		Technologies: []InventoryTechnology{
+			{Name: "Java", Versions: []InventoryVersion{
+				{Version: "8", Resources: []string{"b"}},
+				{Version: "11", Resources: []string{"b", "c"}},
+				{Version: "17", Resources: []string{"a"}},
+			}},
+			{Name: "Spring", Versions: []InventoryVersion{{Version: "2.7.2", Resources: []string{"b"}}}},
+			{Name: "node", Versions: []InventoryVersion{{Version: "21", Resources: []string{"a"}}}},
+		},
+		Resources: []InventoryResource{
+			{Tag: "a", Software: map[string][]string{"Java": {"17"}, "node": {"21"}}},
+			{Tag: "b", Software: map[string][]string{"Java": {"8", "11"}, "Spring": {"2.7.2"}}},
+			{Tag: "c", Software: map[string][]string{"Java": {"11"}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	wantMatrix := "Resource,Java,Spring,node\na,17,,21\nb,\"8, 11\",2.7.2,\nc,11,,\n"
+	if matrix := got.CSV(true); matrix != wantMatrix {
+		t.Errorf("got matrix %q, want %q", matrix, wantMatrix)
+	}
+}
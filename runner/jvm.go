@@ -0,0 +1,374 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// JVM software labels.
+const (
+	labelSpring    = "Spring"
+	labelKotlin    = "Kotlin"
+	labelJava      = "Java"
+	labelJdkTarget = "JDK target"
+)
+
+// jvmVersion drops legacy `1.` prefix of Java versions, so 1.8 and VERSION_1_8 both become 8.
+func jvmVersion(version string) string {
+	version = strings.ReplaceAll(version, "_", ".")
+	if rest, ok := strings.CutPrefix(version, "1."); ok && len(rest) > 0 && rest[0] >= '5' && rest[0] <= '8' {
+		return rest
+	}
+	return version
+}
+
+// jvmLabel builds software label, Java versions are normalised and unresolved versions are dropped.
+func jvmLabel(name string, version string) string {
+	version = strings.Trim(strings.TrimSpace(version), `"'`)
+	if len(version) == 0 || strings.ContainsAny(version, "${}") {
+		return ""
+	}
+	if name == labelJava || name == labelJdkTarget {
+		version = jvmVersion(version)
+	}
+	return name + " " + version
+}
+
+// jvmProperty maps property or catalog key to label, keys are compared without case and separators.
+func jvmProperty(key string) string {
+	normalised := strings.ToLower(nonAlphanumericRegex.ReplaceAllString(strings.ReplaceAll(key, " ", ""), ""))
+	switch normalised {
+	case "springboot", "springbootversion":
+		return labelSpring
+	case "kotlin", "kotlinversion":
+		return labelKotlin
+	case "java", "javaversion", "jdk", "jdkversion", "javatoolchain", "javatoolchainversion", "javalanguageversion":
+		return labelJava
+	case "jvmtarget", "javatarget", "targetcompatibility", "mavencompilerrelease", "mavencompilertarget":
+		return labelJdkTarget
+	}
+	return ""
+}
+
+// mavenDetector reads pom.xml together with parents found in repository.
+type mavenDetector struct {
+	passthroughNormalize
+}
+
+type pomArtifact struct {
+	GroupId      string  `xml:"groupId"`
+	ArtifactId   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+	Release      string  `xml:"configuration>release"`
+	Target       string  `xml:"configuration>target"`
+	JvmTarget    string  `xml:"configuration>jvmTarget"`
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pom struct {
+	Parent     pomArtifact `xml:"parent"`
+	Properties struct {
+		Entries []pomProperty `xml:",any"`
+	} `xml:"properties"`
+	Managed          []pomArtifact `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins          []pomArtifact `xml:"build>plugins>plugin"`
+	PluginManagement []pomArtifact `xml:"build>pluginManagement>plugins>plugin"`
+}
+
+func (mavenDetector) Name() string {
+	return "maven"
+}
+
+func (mavenDetector) Matches(path string) bool {
+	return matchesAny([]string{"pom.xml"}, path)
+}
+
+func (d mavenDetector) Extract(file SourceFile) []string {
+	var project pom
+	if err := xml.Unmarshal(file.Content, &project); err != nil {
+		return []string{}
+	}
+	properties := d.properties(file, project, 0)
+	resolve := func(value string) string {
+		return pomPropertyReg.ReplaceAllStringFunc(strings.TrimSpace(value), func(match string) string {
+			if resolved, ok := properties[match[2:len(match)-1]]; ok {
+				return resolved
+			}
+			return match
+		})
+	}
+
+	values := []string{}
+	add := func(name string, version string) {
+		if label := jvmLabel(name, resolve(version)); len(label) > 0 {
+			values = append(values, label)
+		}
+	}
+
+	if project.Parent.GroupId == "org.springframework.boot" {
+		add(labelSpring, project.Parent.Version)
+	}
+	for _, artifact := range append(append(project.Managed, project.Plugins...), project.PluginManagement...) {
+		switch {
+		case artifact.GroupId == "org.springframework.boot":
+			add(labelSpring, artifact.Version)
+		case artifact.ArtifactId == "kotlin-maven-plugin":
+			add(labelKotlin, artifact.Version)
+			add(labelJdkTarget, artifact.JvmTarget)
+		case artifact.ArtifactId == "maven-compiler-plugin":
+			add(labelJdkTarget, artifact.Release)
+			add(labelJdkTarget, artifact.Target)
+		}
+	}
+	for _, name := range sortedKeys(properties) {
+		switch name {
+		case "spring-boot.version", "spring.boot.version":
+			add(labelSpring, properties[name])
+		case "kotlin.version":
+			add(labelKotlin, properties[name])
+		case "java.version":
+			add(labelJava, properties[name])
+		case "maven.compiler.release", "maven.compiler.target", "kotlin.compiler.jvmTarget":
+			add(labelJdkTarget, properties[name])
+		}
+	}
+	return values
+}
+
+var pomPropertyReg = regexp.MustCompile(`\$\{[^}]+\}`)
+
+// properties of pom merged over properties of its parents, parent is looked up by relativePath (../pom.xml by default).
+func (d mavenDetector) properties(file SourceFile, project pom, depth int) map[string]string {
+	properties := map[string]string{}
+	if len(project.Parent.ArtifactId) > 0 && depth < 10 {
+		relative := "../pom.xml"
+		if project.Parent.RelativePath != nil {
+			relative = strings.TrimSpace(*project.Parent.RelativePath)
+		}
+		if len(relative) > 0 {
+			parentPath := path.Join(path.Dir(file.Path), relative)
+			if !strings.HasSuffix(parentPath, ".xml") {
+				parentPath = path.Join(parentPath, "pom.xml")
+			}
+			var parent pom
+			if content, err := file.Read(parentPath); err == nil && xml.Unmarshal(content, &parent) == nil {
+				properties = d.properties(SourceFile{Path: parentPath, root: file.root, inputs: file.inputs}, parent, depth+1)
+			}
+		}
+	}
+	for _, property := range project.Properties.Entries {
+		properties[property.XMLName.Local] = strings.TrimSpace(property.Value)
+	}
+	return properties
+}
+
+// gradleDetector reads Gradle build scripts. Versions given by variables are resolved from script itself
+// and from gradle.properties next to it or in repository root.
+type gradleDetector struct {
+	passthroughNormalize
+}
+
+var gradleRules = []struct {
+	label string
+	reg   *regexp.Regexp
+}{
+	{labelSpring, regexp.MustCompile(`springBootVersion\s*=\s*['"]([^'"]+)['"]`)},
+	{labelSpring, regexp.MustCompile(`id\s*\(?\s*['"]org\.springframework\.boot['"]\s*\)?\s*version\s*\(?\s*([^\s)]+)`)},
+	{labelSpring, regexp.MustCompile(`org\.springframework\.boot:spring-boot-gradle-plugin:([^'"\s)]+)`)},
+	{labelKotlin, regexp.MustCompile(`kotlin\s*\(\s*['"]jvm['"]\s*\)\s*version\s*\(?\s*([^\s)]+)`)},
+	{labelKotlin, regexp.MustCompile(`id\s*\(?\s*['"]org\.jetbrains\.kotlin\.jvm['"]\s*\)?\s*version\s*\(?\s*([^\s)]+)`)},
+	{labelKotlin, regexp.MustCompile(`kotlin_?[vV]ersion\s*=\s*['"]([^'"]+)['"]`)},
+	{labelKotlin, regexp.MustCompile(`org\.jetbrains\.kotlin:kotlin-gradle-plugin:([^'"\s)]+)`)},
+	{labelJava, regexp.MustCompile(`JavaLanguageVersion\.of\(\s*['"]?([^'")\s]+)`)},
+	{labelJdkTarget, regexp.MustCompile(`(?:target|source)Compatibility\s*=\s*(?:JavaVersion\.VERSION_)?['"]?([0-9._]+)`)},
+	{labelJdkTarget, regexp.MustCompile(`jvmTarget\s*(?:=|\.set\()\s*(?:JvmTarget\.JVM_)?['"]?([0-9._]+)`)},
+}
+
+var gradleAssignmentReg = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*=\s*['"]([0-9][^'"\s]*)['"]`)
+var gradleVariableReg = regexp.MustCompile(`^\$?\{?(?:project\.|rootProject\.|ext\.)?([A-Za-z_][A-Za-z0-9_.]*)\}?$`)
+
+func (gradleDetector) Name() string {
+	return "gradle"
+}
+
+func (gradleDetector) Matches(path string) bool {
+	return matchesAny([]string{"build.gradle", "build.gradle.kts"}, path)
+}
+
+func (gradleDetector) Extract(file SourceFile) []string {
+	content := string(file.Content)
+	variables := map[string]string{}
+	for _, match := range gradleAssignmentReg.FindAllStringSubmatch(content, -1) {
+		variables[match[1]] = match[2]
+	}
+	propertiesRead := false
+	resolve := func(value string) string {
+		value = strings.Trim(value, `"'`)
+		if len(value) > 0 && value[0] >= '0' && value[0] <= '9' {
+			return value
+		}
+		match := gradleVariableReg.FindStringSubmatch(value)
+		if match == nil {
+			return ""
+		}
+		if _, ok := variables[match[1]]; !ok && !propertiesRead {
+			propertiesRead = true
+			for _, dir := range unique([]string{".", file.Dir()}) {
+				if content, err := file.Read(path.Join(dir, "gradle.properties")); err == nil {
+					for key, value := range readProperties(content) {
+						variables[key] = value
+					}
+				}
+			}
+		}
+		return variables[match[1]]
+	}
+
+	values := []string{}
+	for _, rule := range gradleRules {
+		for _, match := range rule.reg.FindAllStringSubmatch(content, -1) {
+			if label := jvmLabel(rule.label, resolve(match[1])); len(label) > 0 {
+				values = append(values, label)
+			}
+		}
+	}
+	return values
+}
+
+// readProperties parses java properties file, continuation lines are not supported.
+func readProperties(content []byte) map[string]string {
+	properties := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+		properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return properties
+}
+
+// gradlePropertiesDetector reports versions declared in gradle.properties.
+type gradlePropertiesDetector struct {
+	passthroughNormalize
+}
+
+func (gradlePropertiesDetector) Name() string {
+	return "gradle-properties"
+}
+
+func (gradlePropertiesDetector) Matches(path string) bool {
+	return matchesAny([]string{"gradle.properties"}, path)
+}
+
+func (gradlePropertiesDetector) Extract(file SourceFile) []string {
+	values := []string{}
+	properties := readProperties(file.Content)
+	for _, key := range sortedKeys(properties) {
+		if name := jvmProperty(key); len(name) > 0 {
+			if label := jvmLabel(name, properties[key]); len(label) > 0 {
+				values = append(values, label)
+			}
+		}
+	}
+	return values
+}
+
+// versionCatalogDetector reads Gradle version catalogs: versions, plugins and libraries.
+type versionCatalogDetector struct {
+	passthroughNormalize
+}
+
+func (versionCatalogDetector) Name() string {
+	return "version-catalog"
+}
+
+func (versionCatalogDetector) Matches(path string) bool {
+	return matchesAny([]string{"*.versions.toml"}, path)
+}
+
+func (versionCatalogDetector) Extract(file SourceFile) []string {
+	catalog, err := parseToml(file.Content)
+	if err != nil {
+		return []string{}
+	}
+	versions := tomlMap(catalog, "versions")
+	version := func(entry any) string {
+		switch entry := entry.(type) {
+		case string:
+			return entry
+		case map[string]any:
+			for _, key := range []string{"strictly", "require", "prefer"} {
+				if value := tomlString(entry, key); len(value) > 0 {
+					return value
+				}
+			}
+		}
+		return ""
+	}
+	reference := func(entry map[string]any) string {
+		if ref := tomlString(entry, "version", "ref"); len(ref) > 0 {
+			return version(versions[ref])
+		}
+		return version(entry["version"])
+	}
+
+	values := []string{}
+	add := func(name string, version string) {
+		if label := jvmLabel(name, version); len(name) > 0 && len(label) > 0 {
+			values = append(values, label)
+		}
+	}
+	for _, key := range sortedKeys(versions) {
+		add(jvmProperty(key), version(versions[key]))
+	}
+	plugins := tomlMap(catalog, "plugins")
+	for _, key := range sortedKeys(plugins) {
+		entry, _ := plugins[key].(map[string]any)
+		switch tomlString(entry, "id") {
+		case "org.springframework.boot":
+			add(labelSpring, reference(entry))
+		case "org.jetbrains.kotlin.jvm":
+			add(labelKotlin, reference(entry))
+		}
+	}
+	libraries := tomlMap(catalog, "libraries")
+	for _, key := range sortedKeys(libraries) {
+		module, libraryVersion := "", ""
+		switch entry := libraries[key].(type) {
+		case string:
+			parts := strings.Split(entry, ":")
+			module = strings.Join(parts[:min(2, len(parts))], ":")
+			if len(parts) > 2 {
+				libraryVersion = parts[2]
+			}
+		case map[string]any:
+			module = tomlString(entry, "module")
+			if len(module) == 0 {
+				module = tomlString(entry, "group") + ":" + tomlString(entry, "name")
+			}
+			libraryVersion = reference(entry)
+		}
+		switch {
+		case strings.HasPrefix(module, "org.springframework.boot:spring-boot"):
+			add(labelSpring, libraryVersion)
+		case strings.HasPrefix(module, "org.jetbrains.kotlin:kotlin-stdlib"), module == "org.jetbrains.kotlin:kotlin-gradle-plugin":
+			add(labelKotlin, libraryVersion)
+		}
+	}
+	return values
+}
@@ -0,0 +1,191 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// extract runs detector on file of repository made of files and returns values with files read besides it.
+func extract(t *testing.T, detector Detector, files map[string]string, name string) ([]string, []string) {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, files)
+	if !detector.Matches(name) {
+		t.Fatalf("%s does not match %s", detector.Name(), name)
+	}
+	content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := []string{}
+	values := detector.Extract(SourceFile{Path: name, Content: content, root: root, inputs: &inputs})
+	return values, inputs
+}
+
+func TestMavenDetector(t *testing.T) {
+	parent := `<project>
+  <properties>
+    <java.version>1.8</java.version>
+    <kotlin.version>1.9.0</kotlin.version>
+  </properties>
+</project>`
+	for _, tc := range []struct {
+		name       string
+		files      map[string]string
+		file       string
+		want       []string
+		wantInputs []string
+	}{
+		{
+			name: "spring parent and plugins",
+			files: map[string]string{"pom.xml": `<project>
+  <parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>3.1.0</version><relativePath/></parent>
+  <properties><jvm.target>17</jvm.target></properties>
+  <build><plugins>
+    <plugin><artifactId>maven-compiler-plugin</artifactId><configuration><release>${jvm.target}</release></configuration></plugin>
+  </plugins></build>
+</project>`},
+			file: "pom.xml",
+			want: []string{"Spring 3.1.0", "JDK target 17"},
+		},
+		{
+			name: "properties of parent in parent directory",
+			files: map[string]string{
+				"pom.xml": parent,
+				"app/pom.xml": `<project>
+  <parent><groupId>com.example</groupId><artifactId>root</artifactId><version>1</version></parent>
+  <properties><kotlin.version>2.0.0</kotlin.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.springframework.boot</groupId><artifactId>spring-boot-dependencies</artifactId><version>${spring.boot.version}</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`,
+			},
+			file:       "app/pom.xml",
+			want:       []string{"Java 8", "Kotlin 2.0.0"},
+			wantInputs: []string{"pom.xml"},
+		},
+		{
+			name: "parent by relative path",
+			files: map[string]string{
+				"parent/pom.xml": parent,
+				"app/pom.xml": `<project>
+  <parent><groupId>com.example</groupId><artifactId>root</artifactId><version>1</version><relativePath>../parent</relativePath></parent>
+</project>`,
+			},
+			file:       "app/pom.xml",
+			want:       []string{"Java 8", "Kotlin 1.9.0"},
+			wantInputs: []string{"parent/pom.xml"},
+		},
+		{
+			name:  "invalid xml",
+			files: map[string]string{"pom.xml": "<project>"},
+			file:  "pom.xml",
+			want:  []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, inputs := extract(t, mavenDetector{}, tc.files, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if tc.wantInputs == nil {
+				tc.wantInputs = []string{}
+			}
+			if !reflect.DeepEqual(inputs, tc.wantInputs) {
+				t.Errorf("got inputs %q, want %q", inputs, tc.wantInputs)
+			}
+		})
+	}
+}
+
+func TestGradleDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		files      map[string]string
+		file       string
+		want       []string
+		wantInputs []string
+	}{
+		{
+			name: "groovy",
+			files: map[string]string{"build.gradle": `buildscript { ext { springBootVersion = '2.7.2' } }
+sourceCompatibility = '1.8'`},
+			file: "build.gradle",
+			want: []string{"Spring 2.7.2", "JDK target 8"},
+		},
+		{
+			name: "kotlin dsl",
+			files: map[string]string{"build.gradle.kts": `plugins {
+    id("org.springframework.boot") version "3.2.0"
+    kotlin("jvm") version "1.9.22"
+}
+java { toolchain { languageVersion.set(JavaLanguageVersion.of(21)) } }
+kotlin { compilerOptions { jvmTarget.set(JvmTarget.JVM_21) } }`},
+			file: "build.gradle.kts",
+			want: []string{"Spring 3.2.0", "Kotlin 1.9.22", "Java 21", "JDK target 21"},
+		},
+		{
+			name: "variables from gradle.properties",
+			files: map[string]string{
+				"gradle.properties":     "kotlinVersion=1.9.0\n",
+				"app/gradle.properties": "bootVersion=3.1.5\n",
+				"app/build.gradle":      "plugins {\n  id 'org.springframework.boot' version \"${bootVersion}\"\n  id 'org.jetbrains.kotlin.jvm' version \"$kotlinVersion\"\n}\n",
+			},
+			file:       "app/build.gradle",
+			want:       []string{"Spring 3.1.5", "Kotlin 1.9.0"},
+			wantInputs: []string{"gradle.properties", "app/gradle.properties"},
+		},
+		{
+			name:       "unresolved variable",
+			files:      map[string]string{"build.gradle": "plugins { id 'org.springframework.boot' version \"$bootVersion\" }\n"},
+			file:       "build.gradle",
+			want:       []string{},
+			wantInputs: []string{"gradle.properties"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, inputs := extract(t, gradleDetector{}, tc.files, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if tc.wantInputs == nil {
+				tc.wantInputs = []string{}
+			}
+			if !reflect.DeepEqual(inputs, tc.wantInputs) {
+				t.Errorf("got inputs %q, want %q", inputs, tc.wantInputs)
+			}
+		})
+	}
+}
+
+func TestGradlePropertiesDetector(t *testing.T) {
+	got, _ := extract(t, gradlePropertiesDetector{}, map[string]string{
+		"gradle.properties": "# comment\nspring-boot.version=3.0.1\nkotlin.version: 1.8.10\njavaVersion = 1.8\norg.gradle.jvmargs=-Xmx2g\n",
+	}, "gradle.properties")
+	if want := []string{"Java 8", "Kotlin 1.8.10", "Spring 3.0.1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVersionCatalogDetector(t *testing.T) {
+	got, _ := extract(t, versionCatalogDetector{}, map[string]string{
+		"gradle/libs.versions.toml": `[versions]
+java = "17"
+boot = "3.1.2"
+kotlin = { strictly = "1.9.10" }
+
+[plugins]
+spring-boot = { id = "org.springframework.boot", version.ref = "boot" }
+
+[libraries]
+boot-starter = "org.springframework.boot:spring-boot-starter-web:3.1.3"
+kotlin-stdlib = { group = "org.jetbrains.kotlin", name = "kotlin-stdlib", version.ref = "kotlin" }
+`,
+	}, "gradle/libs.versions.toml")
+	want := []string{"Java 17", "Kotlin 1.9.10", "Spring 3.1.2", "Spring 3.1.3", "Kotlin 1.9.10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
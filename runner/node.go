@@ -0,0 +1,292 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// frontendFrameworks are tracked package.json dependencies with their labels.
+var frontendFrameworks = map[string]string{
+	"typescript":    "Typescript",
+	"react":         "React",
+	"aws-cdk":       "Aws-cdk",
+	"aws-cdk-lib":   "Aws-cdk-lib",
+	"@angular/core": "Angular",
+	"vue":           "Vue",
+	"next":          "Next",
+	"svelte":        "Svelte",
+}
+
+type packageJson struct {
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Engines         map[string]string `json:"engines"`
+}
+
+// frontendDetector reports tracked frameworks of package.json and required node version from engines.
+// Declared range is followed by installed version taken from nearest lockfile in package directory or above it,
+// e.g. "React ^18.2.0 (18.2.1)".
+type frontendDetector struct {
+	passthroughNormalize
+}
+
+func (frontendDetector) Name() string {
+	return "frontend"
+}
+
+func (frontendDetector) Matches(path string) bool {
+	return matchesAny([]string{"package.json"}, path)
+}
+
+func (frontendDetector) Extract(file SourceFile) []string {
+	var manifest packageJson
+	if err := json.Unmarshal(file.Content, &manifest); err != nil {
+		return []string{}
+	}
+	declared := map[string]string{}
+	for name, version := range manifest.DevDependencies {
+		declared[name] = version
+	}
+	for name, version := range manifest.Dependencies {
+		declared[name] = version
+	}
+
+	values := []string{}
+	var lock *lockfile
+	for _, name := range sortedKeys(declared) {
+		label, tracked := frontendFrameworks[name]
+		if !tracked || strings.HasPrefix(declared[name], "workspace:") {
+			continue
+		}
+		if lock == nil {
+			lock = findLockfile(file)
+		}
+		values = append(values, installedLabel(label, declared[name], lock.installed(name, declared[name])))
+	}
+	if node := manifest.Engines["node"]; len(node) > 0 {
+		values = append(values, softwareLabel("Node", node))
+	}
+	return values
+}
+
+// installedLabel reports declared range followed by installed version when they differ.
+func installedLabel(name string, declared string, installed string) string {
+	installed = strings.ReplaceAll(strings.TrimSpace(installed), " ", "")
+	if len(installed) == 0 || installed == strings.TrimSpace(declared) {
+		return softwareLabel(name, declared)
+	}
+	return softwareLabel(name, declared) + " (" + installed + ")"
+}
+
+// nodeVersionDetector reads version files of node version managers.
+type nodeVersionDetector struct {
+	passthroughNormalize
+}
+
+func (nodeVersionDetector) Name() string {
+	return "node-version"
+}
+
+func (nodeVersionDetector) Matches(path string) bool {
+	return matchesAny([]string{".nvmrc", ".node-version"}, path)
+}
+
+func (nodeVersionDetector) Extract(file SourceFile) []string {
+	for _, line := range strings.Split(string(file.Content), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, "#") {
+			return []string{"Node " + strings.TrimPrefix(strings.Fields(line)[0], "v")}
+		}
+	}
+	return []string{}
+}
+
+// lockfile found for package.json, importer is directory of package.json relative to lockfile directory.
+type lockfile struct {
+	kind     string
+	content  []byte
+	importer string
+}
+
+var lockfiles = []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml"}
+
+// findLockfile walks from package.json directory up to repository root.
+func findLockfile(file SourceFile) *lockfile {
+	dir := file.Dir()
+	for {
+		for _, kind := range lockfiles {
+			if content, err := file.Read(path.Join(dir, kind)); err == nil {
+				importer := strings.TrimPrefix(strings.TrimPrefix(file.Dir(), dir), "/")
+				if dir == "." {
+					importer = file.Dir()
+				}
+				if len(importer) == 0 {
+					importer = "."
+				}
+				return &lockfile{kind: kind, content: content, importer: importer}
+			}
+		}
+		if dir == "." {
+			return &lockfile{}
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// installed returns version of dependency resolved in lockfile or empty string.
+func (lock *lockfile) installed(name string, declared string) string {
+	switch lock.kind {
+	case "package-lock.json":
+		return packageLockVersion(lock.content, lock.importer, name)
+	case "yarn.lock":
+		return yarnLockVersion(lock.content, name, declared)
+	case "pnpm-lock.yaml":
+		return pnpmLockVersion(lock.content, lock.importer, name)
+	}
+	return ""
+}
+
+func packageLockVersion(content []byte, importer string, name string) string {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return ""
+	}
+	candidates := []string{"node_modules/" + name}
+	if importer != "." {
+		candidates = append([]string{importer + "/node_modules/" + name}, candidates...)
+	}
+	for _, key := range candidates {
+		if entry, ok := lock.Packages[key]; ok && len(entry.Version) > 0 {
+			return entry.Version
+		}
+	}
+	return lock.Dependencies[name].Version
+}
+
+// yarnLockVersion reads classic and berry yarn.lock, entry with declared range is preferred.
+func yarnLockVersion(content []byte, name string, declared string) string {
+	found := ""
+	matching := false
+	exact := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line[0] != ' ' {
+			matching, exact = false, false
+			for _, spec := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				at := strings.LastIndex(spec, "@")
+				if at <= 0 || spec[:at] != name {
+					continue
+				}
+				matching = true
+				exact = exact || strings.TrimPrefix(spec[at+1:], "npm:") == declared
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if !matching || !strings.HasPrefix(trimmed, "version") {
+			continue
+		}
+		version := strings.Trim(strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(trimmed, "version"), ": ")), `"`)
+		if exact {
+			return version
+		}
+		if len(found) == 0 {
+			found = version
+		}
+	}
+	return found
+}
+
+// pnpmLockVersion reads importers section of pnpm-lock.yaml, or top level dependencies of older single project lockfiles.
+func pnpmLockVersion(content []byte, importer string, name string) string {
+	for _, section := range []string{"dependencies", "devDependencies"} {
+		for _, keys := range [][]string{{"importers", importer, section, name}, {section, name}} {
+			value, found := yamlValue(content, keys...)
+			if !found {
+				continue
+			}
+			if len(value) == 0 {
+				value, _ = yamlValue(content, append(keys, "version")...)
+			}
+			value, _, _ = strings.Cut(value, "(")
+			value, _, _ = strings.Cut(value, "_")
+			if len(value) > 0 {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// yamlValue follows keys through nested block mappings and returns scalar value of last one.
+// Only subset of YAML used by lockfiles is understood.
+func yamlValue(content []byte, keys ...string) (string, bool) {
+	depth := 0
+	parentIndent := -1
+	childIndent := -1
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+		if indent <= parentIndent {
+			return "", false
+		}
+		if childIndent == -1 {
+			childIndent = indent
+		}
+		if indent != childIndent {
+			continue
+		}
+		key, value := yamlEntry(trimmed)
+		if key != keys[depth] {
+			continue
+		}
+		if depth == len(keys)-1 {
+			return value, true
+		}
+		depth++
+		parentIndent = indent
+		childIndent = -1
+	}
+	return "", false
+}
+
+// yamlEntry splits `key: value` line, quotes are removed from both.
+func yamlEntry(line string) (string, string) {
+	var key, rest string
+	if quote := line[0]; quote == '\'' || quote == '"' {
+		end := strings.IndexByte(line[1:], quote)
+		if end < 0 {
+			return "", ""
+		}
+		key, rest = line[1:end+1], line[end+2:]
+		rest = strings.TrimPrefix(rest, ":")
+	} else {
+		var found bool
+		key, rest, found = strings.Cut(line, ":")
+		if !found {
+			return "", ""
+		}
+	}
+	return key, strings.Trim(strings.TrimSpace(rest), `'"`)
+}
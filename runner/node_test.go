@@ -0,0 +1,92 @@
+package runner
+
+import "testing"
+
+const pnpmLock = `lockfileVersion: '9.0'
+
+importers:
+
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.1(react-dom@18.2.0)
+  'packages/web':
+    devDependencies:
+      "typescript": 5.3.3
+      '@angular/core': 17.0.0
+# comment
+packages:
+  react@18.2.1:
+    resolution: {integrity: sha512-x}
+`
+
+func TestYamlValue(t *testing.T) {
+	for _, tc := range []struct {
+		content string
+		keys    []string
+		want    string
+		found   bool
+	}{
+		{pnpmLock, []string{"lockfileVersion"}, "9.0", true},
+		{pnpmLock, []string{"importers", ".", "dependencies", "react", "version"}, "18.2.1(react-dom@18.2.0)", true},
+		{pnpmLock, []string{"importers", ".", "dependencies", "react"}, "", true},
+		{pnpmLock, []string{"importers", "packages/web", "devDependencies", "typescript"}, "5.3.3", true},
+		{pnpmLock, []string{"importers", "packages/web", "devDependencies", "@angular/core"}, "17.0.0", true},
+		{pnpmLock, []string{"importers", ".", "devDependencies"}, "", false},
+		{pnpmLock, []string{"importers", "packages/web", "dependencies", "react"}, "", false},
+		{pnpmLock, []string{"react"}, "", false},
+		{pnpmLock, []string{"specifier"}, "", false},
+		{pnpmLock, []string{"packages", "react@18.2.1", "resolution"}, "{integrity: sha512-x}", true},
+		{"a:\n  b: 1\nc:\n  b: 2\n", []string{"c", "b"}, "2", true},
+		{"a:\n    b: 1\n  c: 2\n", []string{"a", "c"}, "", false},
+		{"a:\nb: 1\n", []string{"a", "b"}, "", false},
+		{"", []string{"a"}, "", false},
+		{"'unterminated: 1\n\"\n:\n", []string{"a"}, "", false},
+	} {
+		got, found := yamlValue([]byte(tc.content), tc.keys...)
+		if got != tc.want || found != tc.found {
+			t.Errorf("yamlValue(%q, %q) = %q, %v, want %q, %v", tc.content, tc.keys, got, found, tc.want, tc.found)
+		}
+	}
+}
+
+func TestPnpmLockVersion(t *testing.T) {
+	for _, tc := range []struct {
+		importer string
+		name     string
+		want     string
+	}{
+		{".", "react", "18.2.1"},
+		{"packages/web", "typescript", "5.3.3"},
+		{"packages/web", "@angular/core", "17.0.0"},
+		{"packages/web", "vue", ""},
+	} {
+		if got := pnpmLockVersion([]byte(pnpmLock), tc.importer, tc.name); got != tc.want {
+			t.Errorf("pnpmLockVersion(%q, %q) = %q, want %q", tc.importer, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestInstalledLabel(t *testing.T) {
+	for _, tc := range []struct {
+		declared  string
+		installed string
+		want      string
+		version   string
+	}{
+		{"^18.2.0", "18.2.1", "React ^18.2.0 (18.2.1)", "^18.2.0 (18.2.1)"},
+		{"18.2.1", "18.2.1", "React 18.2.1", "18.2.1"},
+		{">= 18 < 19", "18.2.1", "React >=18<19 (18.2.1)", ">=18<19 (18.2.1)"},
+		{"^18.2.0", "", "React ^18.2.0", "^18.2.0"},
+		{"", "18.2.1", "React (18.2.1)", "(18.2.1)"},
+	} {
+		got := installedLabel("React", tc.declared, tc.installed)
+		if got != tc.want {
+			t.Errorf("installedLabel(%q, %q) = %q, want %q", tc.declared, tc.installed, got, tc.want)
+		}
+		if name, version := ParseSoftware(got); name != "React" || version != tc.version {
+			t.Errorf("ParseSoftware(%q) = %q, %q, want React, %q", got, name, version, tc.version)
+		}
+	}
+}
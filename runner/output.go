@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion of output.json written by analyzer.
+const SchemaVersion = 2
+
+type Output struct {
+	SchemaVersion int        `json:"schemaVersion"`
+	Resources     []Resource `json:"resources"`
+	Failures      []Failure  `json:"failures,omitempty"`
+}
+
+// legacyResource is resource as written before schemaVersion was introduced.
+type legacyResource struct {
+	Tag        string              `json:"tag"`
+	References map[string][]string `json:"references"`
+	Kinds      map[string][]string `json:"kinds"`
+	Software   []string            `json:"software"`
+}
+
+// ParseOutput reads output.json in current schema or legacy format (plain list of resources).
+func ParseOutput(data []byte) ([]Resource, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		resources, err := parseLegacyOutput(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		return ComputeUsedBy(resources), nil
+	}
+
+	var output Output
+	if err := json.Unmarshal(trimmed, &output); err != nil {
+		return nil, err
+	}
+	if output.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d, max supported is %d", output.SchemaVersion, SchemaVersion)
+	}
+	return ComputeUsedBy(output.Resources), nil
+}
+
+func parseLegacyOutput(data []byte) ([]Resource, error) {
+	var legacy []legacyResource
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0, len(legacy))
+	for _, l := range legacy {
+		references := map[string][]Reference{}
+		for tag, locations := range l.References {
+			kind := ""
+			if len(l.Kinds[tag]) == 1 {
+				kind = l.Kinds[tag][0]
+			}
+			for _, location := range locations {
+				ref := parseLegacyLocation(location)
+				ref.Kind = kind
+				references[tag] = append(references[tag], ref)
+			}
+		}
+		resources = append(resources, Resource{
+			Tag:        l.Tag,
+			References: references,
+			Software:   l.Software,
+		})
+	}
+	return resources, nil
+}
+
+// parseLegacyLocation splits "/repository/path/to/file:line" into reference.
+func parseLegacyLocation(location string) Reference {
+	ref := Reference{}
+	path := location
+	if i := strings.LastIndex(location, ":"); i > 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil {
+			ref.Line = line
+			path = location[:i]
+		}
+	}
+	path = strings.TrimPrefix(path, "/")
+	repository, rest, found := strings.Cut(path, "/")
+	if found {
+		ref.Repository = repository
+		ref.Path = rest
+	} else {
+		ref.Path = path
+	}
+	return ref
+}
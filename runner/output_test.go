@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  []Resource
+		err   bool
+	}{
+		{
+			name: "legacy",
+			input: ` [
+  {"tag": "web", "references": {"api": ["/web/src/client.ts:12", "/web/README.md"]}, "kinds": {"api": ["http"]}, "software": ["Node 20"]},
+  {"tag": "api", "references": {"db": ["/api/config.yml:3"]}, "kinds": {"db": ["http", "jdbc"]}}
+]`,
+			want: []Resource{
+				{Tag: "web", Software: []string{"Node 20"}, References: map[string][]Reference{"api": {
+					{Repository: "web", Path: "src/client.ts", Line: 12, Kind: "http"},
+					{Repository: "web", Path: "README.md", Kind: "http"},
+				}}},
+				{Tag: "api", UsedBy: []string{"web"}, References: map[string][]Reference{"db": {
+					{Repository: "api", Path: "config.yml", Line: 3},
+				}}},
+			},
+		},
+		{
+			name:  "legacy location without repository",
+			input: `[{"tag": "a", "references": {"b": ["file.txt:x"]}}]`,
+			want: []Resource{
+				{Tag: "a", References: map[string][]Reference{"b": {{Path: "file.txt:x"}}}},
+			},
+		},
+		{
+			name:  "current schema",
+			input: `{"schemaVersion": 2, "resources": [{"tag": "a", "references": {"b": [{"repository": "a", "path": "x.go", "line": 1, "column": 2, "match": "b"}]}}, {"tag": "b", "references": {}}]}`,
+			want: []Resource{
+				{Tag: "a", References: map[string][]Reference{"b": {{Repository: "a", Path: "x.go", Line: 1, Column: 2, Match: "b"}}}},
+				{Tag: "b", UsedBy: []string{"a"}, References: map[string][]Reference{}},
+			},
+		},
+		{
+			name:  "future schema",
+			input: `{"schemaVersion": 3, "resources": []}`,
+			err:   true,
+		},
+		{
+			name:  "malformed",
+			input: `[{"tag": 1}]`,
+			err:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseOutput([]byte(tc.input))
+			if (err != nil) != tc.err {
+				t.Fatalf("error = %v, want error %v", err, tc.err)
+			}
+			if !tc.err && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GeneratePlantUML renders PlantUML component diagram, or C4-PlantUML container diagram when c4 is set,
+// with the same options as GenerateFlowchart. Groups become boundaries and detected software is used as technology.
+func GeneratePlantUML(resources []Resource, tag string, direction string, exclude []string, groups map[string][]string, renderOrphans bool,
+	validTags []string, tmap map[string]string, c4 bool) string {
+	c := buildChart(resources, tag, direction, exclude, groups, renderOrphans, validTags)
+	ids := plantUMLIds(c)
+
+	element := func(node string) string {
+		technology := strings.Join(c.resources[node].Software, ", ")
+		name := plantUMLQuote(plainLabel(label(node, tmap)))
+		if c4 {
+			return fmt.Sprintf("Container(%s, %s, %s)\n", ids[node], name, plantUMLQuote(technology))
+		}
+		if len(technology) > 0 {
+			return fmt.Sprintf("component %s as %s <<%s>>\n", name, ids[node], technology)
+		}
+		return fmt.Sprintf("component %s as %s\n", name, ids[node])
+	}
+	boundary := func(index int, name string) string {
+		if c4 {
+			return fmt.Sprintf("System_Boundary(b%d, %s) {\n", index, plantUMLQuote(name))
+		}
+		return fmt.Sprintf("package %s {\n", plantUMLQuote(name))
+	}
+
+	uml := "@startuml\n"
+	if c4 {
+		uml += "!include <C4/C4_Container>\n"
+	} else {
+		uml += "skinparam componentStyle rectangle\n"
+	}
+	for i, group := range sortedKeys(c.clusters) {
+		uml += boundary(i, group)
+		for _, node := range c.clusters[group] {
+			uml += "\t" + element(node)
+		}
+		uml += "}\n"
+	}
+	if len(c.orphans) > 0 {
+		uml += boundary(len(c.clusters), "Orphans")
+		for _, node := range c.orphans {
+			uml += "\t" + element(node)
+		}
+		uml += "}\n"
+	}
+	for _, node := range c.ungrouped {
+		uml += element(node)
+	}
+	for _, edge := range c.edges {
+		kinds := strings.Join(c.resources[edge.Source].Kinds(edge.Target), ", ")
+		if c4 {
+			if len(kinds) == 0 {
+				kinds = "uses"
+			}
+			uml += fmt.Sprintf("Rel(%s, %s, %s)\n", ids[edge.Source], ids[edge.Target], plantUMLQuote(kinds))
+		} else if len(kinds) > 0 {
+			uml += fmt.Sprintf("%s --> %s : %s\n", ids[edge.Source], ids[edge.Target], kinds)
+		} else {
+			uml += fmt.Sprintf("%s --> %s\n", ids[edge.Source], ids[edge.Target])
+		}
+	}
+	uml += "@enduml\n"
+	return uml
+}
+
+var plantUMLIdRegex = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// plantUMLIds assigns unique identifiers derived from tags.
+func plantUMLIds(c chart) map[string]string {
+	nodes := append(append([]string{}, c.ungrouped...), c.orphans...)
+	for _, group := range sortedKeys(c.clusters) {
+		nodes = append(nodes, c.clusters[group]...)
+	}
+
+	ids := map[string]string{}
+	used := map[string]bool{}
+	for _, node := range nodes {
+		base := "r_" + plantUMLIdRegex.ReplaceAllString(node, "_")
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[id] = true
+		ids[node] = id
+	}
+	return ids
+}
+
+func plantUMLQuote(value string) string {
+	return "\"" + strings.ReplaceAll(value, "\"", "'") + "\""
+}
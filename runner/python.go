@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// pythonFrameworks are tracked packages by normalised name (PEP 503).
+var pythonFrameworks = map[string]string{
+	"django":  "Django",
+	"flask":   "Flask",
+	"fastapi": "FastAPI",
+	"celery":  "Celery",
+}
+
+var pythonRequirementReg = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*([^;#]*)`)
+var pythonNameReg = regexp.MustCompile(`[-_.]+`)
+
+// pythonRequirement returns label of tracked framework in PEP 508 requirement, exact pins are reported as bare version.
+func pythonRequirement(requirement string) string {
+	match := pythonRequirementReg.FindStringSubmatch(requirement)
+	if match == nil {
+		return ""
+	}
+	label, tracked := pythonFrameworks[strings.ToLower(pythonNameReg.ReplaceAllString(match[1], "-"))]
+	if !tracked {
+		return ""
+	}
+	return softwareLabel(label, pythonVersion(match[2]))
+}
+
+// pythonVersion strips `==` of exact pin, other specifiers are kept.
+func pythonVersion(specifier string) string {
+	specifier = strings.TrimSpace(specifier)
+	if version, ok := strings.CutPrefix(specifier, "=="); ok && !strings.Contains(version, ",") {
+		return strings.TrimSpace(version)
+	}
+	return specifier
+}
+
+// pythonDetector reads pyproject.toml (PEP 621 and Poetry), requirements files and setup.cfg.
+type pythonDetector struct {
+	passthroughNormalize
+}
+
+func (pythonDetector) Name() string {
+	return "python"
+}
+
+func (pythonDetector) Matches(path string) bool {
+	return matchesAny([]string{"pyproject.toml", "requirements*.txt", "setup.cfg", ".python-version"}, path)
+}
+
+func (d pythonDetector) Extract(file SourceFile) []string {
+	switch name := file.Path[strings.LastIndex(file.Path, "/")+1:]; {
+	case name == "pyproject.toml":
+		return d.pyproject(file.Content)
+	case name == "setup.cfg":
+		return d.setupCfg(file.Content)
+	case name == ".python-version":
+		if fields := strings.Fields(string(file.Content)); len(fields) > 0 {
+			return []string{softwareLabel("Python", fields[0])}
+		}
+		return []string{}
+	}
+	values := []string{}
+	for _, line := range strings.Split(string(file.Content), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "-") {
+			values = appendLabel(values, pythonRequirement(line))
+		}
+	}
+	return values
+}
+
+func (pythonDetector) pyproject(content []byte) []string {
+	project, err := parseToml(content)
+	if err != nil {
+		return []string{}
+	}
+	values := []string{}
+	if python := tomlString(project, "project", "requires-python"); len(python) > 0 {
+		values = append(values, softwareLabel("Python", python))
+	}
+	if dependencies, ok := tomlMap(project, "project")["dependencies"].([]any); ok {
+		for _, dependency := range dependencies {
+			if requirement, ok := dependency.(string); ok {
+				values = appendLabel(values, pythonRequirement(requirement))
+			}
+		}
+	}
+	poetry := tomlMap(project, "tool", "poetry", "dependencies")
+	for _, name := range sortedKeys(poetry) {
+		version, _ := poetry[name].(string)
+		if table, ok := poetry[name].(map[string]any); ok {
+			version = tomlString(table, "version")
+		}
+		if name == "python" {
+			values = append(values, softwareLabel("Python", version))
+		} else if label, tracked := pythonFrameworks[strings.ToLower(pythonNameReg.ReplaceAllString(name, "-"))]; tracked {
+			values = append(values, softwareLabel(label, version))
+		}
+	}
+	return values
+}
+
+// setupCfg reads python_requires and install_requires of [options] section.
+func (pythonDetector) setupCfg(content []byte) []string {
+	values := []string{}
+	section, key := "", ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			section, key = strings.Trim(trimmed, "[]"), ""
+			continue
+		}
+		if section != "options" {
+			continue
+		}
+		value := trimmed
+		if line[0] != ' ' && line[0] != '\t' {
+			name, rest, _ := strings.Cut(line, "=")
+			key, value = strings.TrimSpace(name), strings.TrimSpace(rest)
+		}
+		switch key {
+		case "python_requires":
+			values = append(values, softwareLabel("Python", value))
+		case "install_requires":
+			values = appendLabel(values, pythonRequirement(value))
+		}
+	}
+	return values
+}
+
+// appendLabel appends non-empty label.
+func appendLabel(values []string, label string) []string {
+	if len(label) > 0 {
+		return append(values, label)
+	}
+	return values
+}
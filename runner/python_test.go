@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPythonDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{"requirements", "requirements-dev.txt", "# web\nDjango==4.2.1\nflask[async] >=2.0,<3 ; python_version > '3.8'\nrequests==2.31\n-r base.txt\n", []string{"Django 4.2.1", "Flask >=2.0,<3"}},
+		{"pep 621", "pyproject.toml", "[project]\nrequires-python = \">=3.11\"\ndependencies = [\"fastapi==0.110.0\", \"uvicorn\"]\n", []string{"Python >=3.11", "FastAPI 0.110.0"}},
+		{"poetry", "pyproject.toml", "[tool.poetry.dependencies]\npython = \"^3.10\"\nDjango = { version = \"^5.0\", extras = [\"argon2\"] }\nCelery = \"5.3.6\"\n", []string{"Celery 5.3.6", "Django ^5.0", "Python ^3.10"}},
+		{"setup.cfg", "setup.cfg", "[metadata]\nname = app\n\n[options]\npython_requires = >=3.9\ninstall_requires =\n    flask==3.0.0\n    click\n", []string{"Python >=3.9", "Flask 3.0.0"}},
+		{"python-version", ".python-version", "3.12.1\n", []string{"Python 3.12.1"}},
+		{"invalid pyproject", "pyproject.toml", "[project", []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := extract(t, pythonDetector{}, map[string]string{tc.file: tc.content}, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
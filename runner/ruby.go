@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// rubyGems are tracked gems.
+var rubyGems = map[string]string{
+	"rails":   "Rails",
+	"sinatra": "Sinatra",
+	"hanami":  "Hanami",
+	"grape":   "Grape",
+}
+
+var gemfileRubyReg = regexp.MustCompile(`(?m)^\s*ruby\s+['"]([^'"]+)['"]`)
+var gemfileGemReg = regexp.MustCompile(`(?m)^\s*gem\s+['"]([^'"]+)['"]\s*(?:,\s*['"]([^'"]+)['"])?`)
+var gemfileLockGemReg = regexp.MustCompile(`(?m)^    ([A-Za-z0-9_.-]+) \(([^)]+)\)$`)
+var gemfileLockRubyReg = regexp.MustCompile(`(?m)^RUBY VERSION\s+ruby ([0-9.]+)`)
+
+// rubyDetector reports ruby version and tracked gems of Gemfile, resolved with Gemfile.lock next to it when present,
+// and version from .ruby-version.
+type rubyDetector struct {
+	passthroughNormalize
+}
+
+func (rubyDetector) Name() string {
+	return "ruby"
+}
+
+func (rubyDetector) Matches(path string) bool {
+	return matchesAny([]string{"Gemfile", ".ruby-version"}, path)
+}
+
+func (rubyDetector) Extract(file SourceFile) []string {
+	content := string(file.Content)
+	if strings.HasSuffix(file.Path, ".ruby-version") {
+		if fields := strings.Fields(content); len(fields) > 0 {
+			return []string{softwareLabel("Ruby", strings.TrimPrefix(fields[0], "ruby-"))}
+		}
+		return []string{}
+	}
+
+	locked := map[string]string{}
+	lockedRuby := ""
+	if lock, err := file.Read(path.Join(file.Dir(), "Gemfile.lock")); err == nil {
+		for _, match := range gemfileLockGemReg.FindAllStringSubmatch(string(lock), -1) {
+			locked[match[1]] = match[2]
+		}
+		if match := gemfileLockRubyReg.FindStringSubmatch(string(lock)); match != nil {
+			lockedRuby = match[1]
+		}
+	}
+
+	values := []string{}
+	if match := gemfileRubyReg.FindStringSubmatch(content); match != nil {
+		values = append(values, softwareLabel("Ruby", match[1]))
+	} else if len(lockedRuby) > 0 {
+		values = append(values, softwareLabel("Ruby", lockedRuby))
+	}
+	for _, match := range gemfileGemReg.FindAllStringSubmatch(content, -1) {
+		label, tracked := rubyGems[match[1]]
+		if !tracked {
+			continue
+		}
+		version := match[2]
+		if resolved, ok := locked[match[1]]; ok {
+			version = resolved
+		}
+		values = append(values, softwareLabel(label, version))
+	}
+	return values
+}
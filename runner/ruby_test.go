@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRubyDetector(t *testing.T) {
+	gemfile := "source 'https://rubygems.org'\nruby '3.2.2'\ngem 'rails', '~> 7.1'\ngem \"sinatra\"\ngem 'puma'\n"
+	for _, tc := range []struct {
+		name       string
+		files      map[string]string
+		file       string
+		want       []string
+		wantInputs []string
+	}{
+		{"gemfile", map[string]string{"Gemfile": gemfile}, "Gemfile", []string{"Ruby 3.2.2", "Rails ~>7.1", "Sinatra"}, []string{"Gemfile.lock"}},
+		{
+			name: "resolved with lock",
+			files: map[string]string{
+				"app/Gemfile":      "gem 'rails', '~> 7.1'\n",
+				"app/Gemfile.lock": "GEM\n  specs:\n    rails (7.1.3)\n      actionpack (= 7.1.3)\n\nRUBY VERSION\n   ruby 3.3.0p0\n",
+			},
+			file:       "app/Gemfile",
+			want:       []string{"Ruby 3.3.0", "Rails 7.1.3"},
+			wantInputs: []string{"app/Gemfile.lock"},
+		},
+		{"ruby-version", map[string]string{".ruby-version": "ruby-3.1.4\n"}, ".ruby-version", []string{"Ruby 3.1.4"}, []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, inputs := extract(t, rubyDetector{}, tc.files, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if !reflect.DeepEqual(inputs, tc.wantInputs) {
+				t.Errorf("got inputs %q, want %q", inputs, tc.wantInputs)
+			}
+		})
+	}
+}
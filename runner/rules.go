@@ -0,0 +1,142 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rule types: edges from→to are forbidden, sources may depend only on allowed targets, limit of distinct dependencies.
+const (
+	RuleForbidden = "forbidden"
+	RuleAllowed   = "allowed"
+	RuleMaxFanOut = "maxFanOut"
+)
+
+// Rule selects resources with From and To, each entry is tag, "group:<name>" or "*". Empty From means all resources.
+// Max is required by maxFanOut rules.
+type Rule struct {
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+	From []string `json:"from"`
+	To   []string `json:"to"`
+	Max  *int     `json:"max"`
+}
+
+type Violation struct {
+	Rule       string      `json:"rule"`
+	Source     string      `json:"source"`
+	Target     string      `json:"target,omitempty"`
+	Message    string      `json:"message"`
+	References []Reference `json:"references,omitempty"`
+}
+
+func (v Violation) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("[%s] %s\n", v.Rule, v.Message))
+	for _, ref := range v.References {
+		sb.WriteString(fmt.Sprintf("\t%s\n", ref))
+	}
+	return sb.String()
+}
+
+// ParseRules reads json array of rules and validates them.
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if len(rule.Name) == 0 {
+			rules[i].Name = fmt.Sprintf("%s#%d", rule.Type, i+1)
+		}
+		switch rule.Type {
+		case RuleForbidden, RuleAllowed:
+			if len(rule.To) == 0 {
+				return nil, fmt.Errorf("rule %s: to is required", rules[i].Name)
+			}
+		case RuleMaxFanOut:
+			if rule.Max == nil {
+				return nil, fmt.Errorf("rule %s: max is required", rules[i].Name)
+			}
+			if *rule.Max < 0 {
+				return nil, fmt.Errorf("rule %s: max must not be negative", rules[i].Name)
+			}
+		default:
+			return nil, fmt.Errorf("rule %s: unknown type %q", rules[i].Name, rule.Type)
+		}
+	}
+	return rules, nil
+}
+
+// CheckRules evaluates rules against visible edges, groups are used by "group:<name>" selectors.
+// Selector of group missing in groups is an error.
+func CheckRules(resources []Resource, rules []Rule, groups map[string][]string, exclude []string, validTags []string) ([]Violation, error) {
+	for _, rule := range rules {
+		for _, selector := range append(slices.Clone(rule.From), rule.To...) {
+			if name, ok := strings.CutPrefix(selector, "group:"); ok {
+				if _, defined := groups[name]; !defined {
+					return nil, fmt.Errorf("rule %s: group %q is not defined in group definitions", rule.Name, name)
+				}
+			}
+		}
+	}
+
+	violations := []Violation{}
+	sorted := slices.Clone(resources)
+	slices.SortFunc(sorted, func(a, b Resource) int { return strings.Compare(a.Tag, b.Tag) })
+
+	for _, rule := range rules {
+		for _, resource := range sorted {
+			source := resource.Tag
+			if !visible(source, exclude, validTags) || (len(rule.From) > 0 && !selects(rule.From, source, groups)) {
+				continue
+			}
+			targets := []string{}
+			for _, target := range sortedKeys(resource.References) {
+				if visible(target, exclude, validTags) {
+					targets = append(targets, target)
+				}
+			}
+
+			switch rule.Type {
+			case RuleForbidden, RuleAllowed:
+				for _, target := range targets {
+					if selects(rule.To, target, groups) != (rule.Type == RuleForbidden) {
+						continue
+					}
+					violations = append(violations, Violation{
+						Rule:       rule.Name,
+						Source:     source,
+						Target:     target,
+						Message:    fmt.Sprintf("%s must not depend on %s", source, target),
+						References: resource.References[target],
+					})
+				}
+			case RuleMaxFanOut:
+				if len(targets) > *rule.Max {
+					violations = append(violations, Violation{
+						Rule:    rule.Name,
+						Source:  source,
+						Message: fmt.Sprintf("%s depends on %d resources, allowed %d: %s", source, len(targets), *rule.Max, strings.Join(targets, ", ")),
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// selects reports whether tag matches any of selectors.
+func selects(selectors []string, tag string, groups map[string][]string) bool {
+	for _, selector := range selectors {
+		if selector == "*" || selector == tag {
+			return true
+		}
+		if name, ok := strings.CutPrefix(selector, "group:"); ok && slices.Contains(groups[name], tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package runner
+
+import "testing"
+
+func TestCheckRulesUndefinedGroup(t *testing.T) {
+	resources := []Resource{
+		{Tag: "a", References: map[string][]Reference{"b": {{Path: "main.go"}}}},
+		{Tag: "b"},
+	}
+	rules := []Rule{{Name: "isolated", Type: RuleForbidden, From: []string{"group:core"}, To: []string{"b"}}}
+
+	if _, err := CheckRules(resources, rules, map[string][]string{}, nil, nil); err == nil {
+		t.Error("expected error for undefined group")
+	}
+	violations, err := CheckRules(resources, rules, map[string][]string{"core": {"a"}}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Source != "a" || violations[0].Target != "b" {
+		t.Errorf("got %+v, want single violation a -> b", violations)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   bool
+	}{
+		{`[{"type": "maxFanOut", "max": 0}]`, false},
+		{`[{"type": "maxFanOut", "max": 3, "from": ["group:core"]}]`, false},
+		{`[{"type": "maxFanOut"}]`, true},
+		{`[{"type": "maxFanOut", "max": -1}]`, true},
+		{`[{"type": "forbidden", "from": ["a"], "to": ["b"]}]`, false},
+		{`[{"type": "forbidden", "from": ["a"]}]`, true},
+		{`[{"type": "allowed"}]`, true},
+		{`[{"type": "unknown", "to": ["b"]}]`, true},
+		{`{}`, true},
+	} {
+		if _, err := ParseRules([]byte(tc.input)); (err != nil) != tc.err {
+			t.Errorf("ParseRules(%s) error = %v, want error %v", tc.input, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,521 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Repository struct {
+	Url  string `json:"url"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path"`
+	Ref  string `json:"ref"`
+}
+
+type Resource struct {
+	Tag        string                 `json:"tag"`
+	Commit     string                 `json:"commit,omitempty"`
+	References map[string][]Reference `json:"references"`
+	UsedBy     []string               `json:"usedBy,omitempty"`
+	Software   []string               `json:"software"`
+	Images     []BaseImage            `json:"images,omitempty"`
+}
+
+// Reference is single occurrence of referenced tag.
+type Reference struct {
+	Repository string `json:"repository"`
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Match      string `json:"match"`
+	Pattern    string `json:"pattern,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s/%s:%d", r.Repository, r.Path, r.Line)
+}
+
+// Kinds returns distinct kinds of references to given tag.
+func (r Resource) Kinds(tag string) []string {
+	kinds := []string{}
+	for _, ref := range r.References[tag] {
+		if len(ref.Kind) > 0 {
+			kinds = append(kinds, ref.Kind)
+		}
+	}
+	return unique(kinds)
+}
+
+type Pattern struct {
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Regexp     *regexp.Regexp `json:"reg"`
+	Group      string         `json:"group"`
+	TrimSuffix string         `json:"trimSuffix"`
+}
+
+type Config struct {
+	ReferenceRegexp *regexp.Regexp    `json:"reg"`
+	Patterns        []Pattern         `json:"patterns"`
+	RootLike        []string          `json:"rootlike"`
+	Concurrency     int16             `json:"concurrency"`
+	InputFile       string            `json:"input"`
+	OutputFile      string            `json:"output"`
+	TrimSuffix      string            `json:"trimSuffix"`
+	Sync            bool              `json:"sync"`
+	ExtendedSearch  bool              `json:"extendedSearch"`
+	Aliases         map[string]string `json:"aliases"`
+	Ref             string            `json:"ref"`
+	Include         []string          `json:"include"`
+	Exclude         []string          `json:"exclude"`
+	MaxFileSize     int64             `json:"maxFileSize"`
+	FailFast        bool              `json:"failFast"`
+	DisableCache    bool              `json:"disableCache"`
+	Detectors       []DetectorConfig  `json:"detectors"`
+	Images          map[string]string `json:"images"`
+}
+
+type ExecutionConfig struct {
+	Config
+	Repositories []Repository
+	ValidNames   []string
+	WorkDir      string
+	CacheDir     string
+	Patterns     []Pattern
+	Detectors    []Detector
+	progress     func(Event)
+}
+
+func (executionConfig ExecutionConfig) emit(event Event) {
+	if executionConfig.progress != nil {
+		executionConfig.progress(event)
+	}
+}
+
+type collector struct {
+	executionConfig ExecutionConfig
+	resources       map[string]Resource
+	failures        []Failure
+	lock            sync.Mutex
+}
+
+func executionConfig(config Config, repositories []Repository) (ExecutionConfig, error) {
+	patterns, err := resolvePatterns(config)
+	if err != nil {
+		return ExecutionConfig{}, err
+	}
+	detectors, err := resolveDetectors(config)
+	if err != nil {
+		return ExecutionConfig{}, err
+	}
+	validNames := []string{}
+	if !config.ExtendedSearch {
+		for _, r := range repositories {
+			validNames = append(validNames, r.Name)
+		}
+		for fromTag, toAliass := range config.Aliases {
+			validNames = append(validNames, fromTag, toAliass)
+		}
+		validNames = unique(validNames)
+	}
+	return ExecutionConfig{
+		Config:       config,
+		Repositories: repositories,
+		ValidNames:   validNames,
+		Patterns:     patterns,
+		Detectors:    detectors,
+	}, nil
+}
+
+// resolvePatterns returns configured patterns, falling back to legacy `reg` and `trimSuffix` pair.
+func resolvePatterns(config Config) ([]Pattern, error) {
+	patterns := config.Patterns
+	if len(patterns) == 0 && config.ReferenceRegexp != nil {
+		patterns = []Pattern{{
+			Regexp:     config.ReferenceRegexp,
+			TrimSuffix: config.TrimSuffix,
+		}}
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("no reference patterns configured, use `patterns` or `reg`")
+	}
+	for i, p := range patterns {
+		if p.Regexp == nil {
+			return nil, fmt.Errorf("pattern %d (%s) has no regexp", i, p.Name)
+		}
+		if p.tagGroup() < 1 {
+			return nil, fmt.Errorf("pattern %d (%s) has no capture group for tag", i, p.Name)
+		}
+	}
+	return patterns, nil
+}
+
+// tagGroup returns index of capture group holding the tag: configured group, group named `tag` or first one.
+func (p Pattern) tagGroup() int {
+	if len(p.Group) > 0 {
+		if index, err := strconv.Atoi(p.Group); err == nil {
+			if index > p.Regexp.NumSubexp() {
+				return -1
+			}
+			return index
+		}
+		return p.Regexp.SubexpIndex(p.Group)
+	}
+	if index := p.Regexp.SubexpIndex("tag"); index > 0 {
+		return index
+	}
+	if p.Regexp.NumSubexp() == 0 {
+		return -1
+	}
+	return 1
+}
+
+func (p Pattern) kind() string {
+	if len(p.Kind) > 0 {
+		return p.Kind
+	}
+	return p.Name
+}
+
+func (collector *collector) outputResourcesList() []Resource {
+	v := make([]Resource, 0)
+
+	for _, res := range collector.resources {
+		v = append(v, res)
+	}
+	return v
+}
+
+func (collector *collector) merge(newResources []Resource) {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	for _, newResource := range newResources {
+		resource := collector.resources[newResource.Tag]
+		merged := mergeRefs(resource.References, newResource.References, collector.executionConfig.ValidNames)
+		mergedSoftware := unique(append(resource.Software, newResource.Software...))
+
+		commit := newResource.Commit
+		if len(commit) == 0 {
+			commit = resource.Commit
+		}
+
+		collector.resources[newResource.Tag] = Resource{
+			Tag:        newResource.Tag,
+			Commit:     commit,
+			References: merged,
+			Software:   mergedSoftware,
+			Images:     append(resource.Images, newResource.Images...),
+		}
+
+		if len(collector.executionConfig.ValidNames) > 0 {
+			toRemove := []string{}
+			for key := range collector.resources {
+				if !slices.Contains(collector.executionConfig.ValidNames, key) {
+					toRemove = append(toRemove, key)
+				}
+			}
+			for _, removeKey := range toRemove {
+				delete(collector.resources, removeKey)
+			}
+		}
+	}
+
+}
+
+// Failure of single repository, scan continues without it unless FailFast is set.
+type Failure struct {
+	Repository string `json:"repository"`
+	Stage      string `json:"stage"`
+	Error      string `json:"error"`
+}
+
+// Stages of repository processing reported in failures.
+const (
+	StageFetch = "fetch"
+	StageScan  = "scan"
+)
+
+type stageError struct {
+	stage string
+	err   error
+}
+
+func (e stageError) Error() string {
+	return fmt.Sprintf("%s: %s", e.stage, e.err)
+}
+
+func (e stageError) Unwrap() error {
+	return e.err
+}
+
+func (collector *collector) fail(repo Repository, err error) {
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	stage := StageScan
+	var se stageError
+	if errors.As(err, &se) {
+		stage = se.stage
+		err = se.err
+	}
+	collector.failures = append(collector.failures, Failure{
+		Repository: repo.Name,
+		Stage:      stage,
+		Error:      err.Error(),
+	})
+}
+
+func Execute(config Config) error {
+	fmt.Printf("Executing with %+v\n", config)
+	repositories, err := readInputFile(config.InputFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Entries to process: %d\n", len(repositories))
+
+	scanner := NewScanner(config)
+	scanner.WorkDir = "workdir"
+	scanner.CacheDir = filepath.Join(scanner.WorkDir, ".reference-finder-cache")
+	scanner.Progress = printEvent
+	result, err := scanner.Scan(context.Background(), repositories)
+	if err != nil {
+		return err
+	}
+
+	outBytes, err := json.MarshalIndent(Output{
+		SchemaVersion: SchemaVersion,
+		Resources:     result.Resources,
+		Failures:      result.Failures,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reused cached findings of %d repositories, rescanned only changed files of %d\n", result.Reused, result.Incremental)
+	if len(result.Failures) > 0 {
+		fmt.Printf("Failed repositories: %d, see failures in %s\n", len(result.Failures), config.OutputFile)
+	}
+	fmt.Printf("Saving output to file %s\n", config.OutputFile)
+	os.Remove(config.OutputFile)
+	return os.WriteFile(config.OutputFile, outBytes, 0644)
+}
+
+func printEvent(event Event) {
+	switch event.Type {
+	case EventProcessed:
+		mode := ""
+		if event.Mode != ModeScanned {
+			mode = fmt.Sprintf(" (%s)", event.Mode)
+		}
+		fmt.Printf("Processed %d of %d \t %s took %s%s\n", event.Done, event.Total, event.Repository, event.Elapsed, mode)
+	case EventFailed:
+		fmt.Printf("!!!! Failed %d of %d \t %s: %s\n", event.Done, event.Total, event.Repository, event.Err)
+	case EventSkipped:
+		fmt.Printf("Skipped in %s: %s\n", event.Repository, skipSummary(event.Skipped))
+	case EventWarning:
+		fmt.Printf("!!!! %s\n", event.Message)
+	default:
+		fmt.Println(event.Message)
+	}
+}
+
+// Ways repository was processed.
+const (
+	ModeScanned     = "scanned"
+	ModeCached      = "cached"
+	ModeIncremental = "incremental"
+)
+
+// process returns resources found in repository and whether they were scanned, reused from cache
+// or only files changed since cached commit were rescanned.
+func process(ctx context.Context, repo Repository, executionConfig ExecutionConfig) ([]Resource, string, error) {
+
+	location, err := fetchRepo(ctx, repo, executionConfig)
+	if err != nil {
+		return nil, ModeScanned, stageError{StageFetch, err}
+	}
+	source := checkout{
+		repository: repo,
+		path:       location,
+		commit:     resolveCommit(location),
+	}
+
+	useCache := cacheable(ctx, source, executionConfig)
+	var previous *cacheEntry
+	if useCache {
+		if entry, ok := readCache(repo, executionConfig); ok {
+			if entry.Commit == source.commit {
+				emitSkipped(repo, entry, executionConfig)
+				return entry.resources(), ModeCached, nil
+			}
+			previous = &entry
+		}
+	}
+
+	entry, mode, err := scan(ctx, source, previous, executionConfig)
+	if err != nil {
+		return nil, mode, stageError{StageScan, err}
+	}
+	emitSkipped(repo, entry, executionConfig)
+	if useCache {
+		if err := writeCache(repo, entry, executionConfig); err != nil {
+			executionConfig.emit(Event{Type: EventWarning, Repository: repo.Name, Message: fmt.Sprintf("Failed caching findings of %s: %s", repo.Name, err), Err: err})
+		}
+	}
+	return entry.resources(), mode, nil
+}
+
+func emitSkipped(repo Repository, entry cacheEntry, executionConfig ExecutionConfig) {
+	if skipped := entry.skipped(); len(skipped) > 0 {
+		executionConfig.emit(Event{Type: EventSkipped, Repository: repo.Name, Skipped: skipped})
+	}
+}
+
+// scanRoots returns directories producing resources: nested directories of rootlike repository or repository itself.
+func scanRoots(source checkout, executionConfig ExecutionConfig) ([]cachedRoot, error) {
+	repo := source.repository
+	if slices.Contains(executionConfig.RootLike, repo.Name) {
+		entries, err := os.ReadDir(source.path)
+		if err != nil {
+			return nil, err
+		}
+		roots := []cachedRoot{}
+		for _, e := range entries {
+			if e.IsDir() && e.Name() != ".git" {
+				roots = append(roots, cachedRoot{Tag: resolveAlias(e.Name(), executionConfig.Aliases), Dir: e.Name()})
+			}
+		}
+		return roots, nil
+	}
+	return []cachedRoot{{Tag: resolveAlias(repo.Name, executionConfig.Aliases)}}, nil
+}
+
+// scan walks checkout. With previous findings available only files changed since their commit are rescanned.
+func scan(ctx context.Context, source checkout, previous *cacheEntry, executionConfig ExecutionConfig) (cacheEntry, string, error) {
+	entry := cacheEntry{Commit: source.commit}
+
+	roots, err := scanRoots(source, executionConfig)
+	if err != nil {
+		return entry, ModeScanned, err
+	}
+
+	mode := ModeScanned
+	var changed []string
+	if previous != nil {
+		var ok bool
+		if changed, ok = changedFiles(ctx, source, previous.Commit); ok {
+			mode = ModeIncremental
+		}
+	}
+
+	rules := newIgnoreRules(source.path, executionConfig)
+
+	for _, root := range roots {
+		if mode == ModeIncremental {
+			if previousRoot, ok := previous.root(root.Dir); ok {
+				root.Files, root.Skipped, err = rescanChanged(ctx, root, previousRoot, changed, source, rules, executionConfig)
+				if err != nil {
+					return entry, mode, err
+				}
+				entry.Roots = append(entry.Roots, root)
+				continue
+			}
+		}
+
+		findings, err := findReferences(ctx, root.Tag, filepath.Join(source.path, root.Dir), source, rules, executionConfig)
+		if err != nil {
+			return entry, mode, err
+		}
+		root.Files = findings.Files
+		root.Skipped = findings.Skipped
+		entry.Roots = append(entry.Roots, root)
+	}
+	return entry, mode, nil
+}
+
+// rescanChanged carries over findings of untouched files and scans changed ones again, deleted files are dropped.
+// Skipped paths are updated for changed files, skipped directories containing them are counted again.
+func rescanChanged(ctx context.Context, root cachedRoot, previousRoot cachedRoot, changed []string, source checkout, rules *ignoreRules, executionConfig ExecutionConfig) (map[string]FileFindings, skippedPaths, error) {
+	previous := previousRoot.Files
+	skipped := previousRoot.Skipped.clone()
+	recount := map[string]bool{}
+	rescan := slices.Clone(changed)
+	for path, findings := range previous {
+		if !slices.Contains(rescan, path) && slices.ContainsFunc(findings.Inputs, func(input string) bool { return slices.Contains(changed, input) }) {
+			rescan = append(rescan, path)
+		}
+	}
+	slices.Sort(rescan)
+
+	files := map[string]FileFindings{}
+	for path, findings := range previous {
+		if !slices.Contains(rescan, path) {
+			files[path] = findings
+		}
+	}
+
+	for _, path := range rescan {
+		if err := ctx.Err(); err != nil {
+			return nil, skipped, err
+		}
+		if len(root.Dir) > 0 && !strings.HasPrefix(path, root.Dir+"/") {
+			continue
+		}
+		delete(skipped.Files, path)
+		if dir := skipped.dirOf(path); len(dir) > 0 {
+			recount[dir] = true
+			continue
+		}
+		file := filepath.Join(source.path, filepath.FromSlash(path))
+		info, err := os.Lstat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if reason, at := rules.skipWithParents(file, info); len(reason) > 0 {
+			if at == file {
+				skipped.Files[path] = reason
+			} else if rel, err := filepath.Rel(source.path, at); err == nil {
+				skipped.Dirs[filepath.ToSlash(rel)] = skippedDir{Reason: reason, Files: countFiles(at)}
+			}
+			continue
+		}
+		fileFindings, err := scanFile(root.Tag, file, path, source, executionConfig)
+		if err != nil {
+			return nil, skipped, err
+		}
+		if !fileFindings.empty() {
+			files[path] = fileFindings
+		}
+	}
+	for dir := range recount {
+		if _, err := os.Stat(filepath.Join(source.path, filepath.FromSlash(dir))); err != nil {
+			delete(skipped.Dirs, dir)
+			continue
+		}
+		skipped.Dirs[dir] = skippedDir{Reason: skipped.Dirs[dir].Reason, Files: countFiles(filepath.Join(source.path, filepath.FromSlash(dir)))}
+	}
+	return files, skipped, nil
+}
+
+func skipSummary(skipped map[string]int) string {
+	parts := []string{}
+	for _, reason := range []string{SkipGitignore, SkipExcluded, SkipBinary, SkipSize} {
+		if skipped[reason] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", reason, skipped[reason]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %s: %s", args, err, out)
+	}
+}
+
+// scanSkipped scans single local repository and returns scan mode and reported skipped files.
+func scanSkipped(t *testing.T, repo string, cacheDir string, disableCache bool) (string, map[string]int) {
+	t.Helper()
+	config := Config{
+		Patterns:     []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}},
+		DisableCache: disableCache,
+	}
+	config.Exclude = []string{"vendor", "*_test.go"}
+	scanner := NewScanner(config)
+	scanner.CacheDir = cacheDir
+	mode := ""
+	skipped := map[string]int{}
+	scanner.Progress = func(event Event) {
+		switch event.Type {
+		case EventSkipped:
+			skipped = event.Skipped
+		case EventProcessed:
+			mode = event.Mode
+		case EventFailed:
+			t.Fatal(event.Err)
+		}
+	}
+	if _, err := scanner.Scan(context.Background(), []Repository{{Name: "repo", Type: SourceLocal, Path: repo}}); err != nil {
+		t.Fatal(err)
+	}
+	return mode, skipped
+}
+
+func TestSkippedCountsInEveryMode(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	cacheDir := filepath.Join(root, "cache")
+	writeFiles(t, repo, map[string]string{
+		".gitignore":    "*.log\n",
+		"main.go":       "// https://api.service",
+		"main_test.go":  "package main",
+		"vendor/a.go":   "",
+		"vendor/b/c.go": "",
+		"image.bin":     "\x00",
+	})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+	writeFiles(t, repo, map[string]string{"debug.log": ""})
+
+	want := map[string]int{SkipGitignore: 1, SkipExcluded: 3, SkipBinary: 1}
+	for _, wantMode := range []string{ModeScanned, ModeCached} {
+		if mode, skipped := scanSkipped(t, repo, cacheDir, false); mode != wantMode || !reflect.DeepEqual(skipped, want) {
+			t.Errorf("got %s %v, want %s %v", mode, skipped, wantMode, want)
+		}
+	}
+
+	writeFiles(t, repo, map[string]string{
+		"vendor/d.go":     "",
+		"extra/x_test.go": "",
+		"extra/other.bin": "\x00",
+	})
+	os.Remove(filepath.Join(repo, "vendor", "b", "c.go"))
+	os.Remove(filepath.Join(repo, "image.bin"))
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-qm", "second")
+
+	_, full := scanSkipped(t, repo, filepath.Join(root, "fresh"), true)
+	if mode, skipped := scanSkipped(t, repo, cacheDir, false); mode != ModeIncremental || !reflect.DeepEqual(skipped, full) {
+		t.Errorf("got %s %v, want %s %v", mode, skipped, ModeIncremental, full)
+	}
+	if want := (map[string]int{SkipGitignore: 1, SkipExcluded: 4, SkipBinary: 1}); !reflect.DeepEqual(full, want) {
+		t.Errorf("full scan got %v, want %v", full, want)
+	}
+}
@@ -0,0 +1,64 @@
+package runner
+
+import "strings"
+
+// rustCrates are tracked crates.
+var rustCrates = map[string]string{
+	"actix-web": "Actix-web",
+	"axum":      "Axum",
+	"rocket":    "Rocket",
+	"warp":      "Warp",
+	"tokio":     "Tokio",
+}
+
+// rustDetector reports rust-version and edition of Cargo.toml packages, tracked dependencies
+// and toolchain channel of rust-toolchain.toml.
+type rustDetector struct {
+	passthroughNormalize
+}
+
+func (rustDetector) Name() string {
+	return "rust"
+}
+
+func (rustDetector) Matches(path string) bool {
+	return matchesAny([]string{"Cargo.toml", "rust-toolchain.toml"}, path)
+}
+
+func (rustDetector) Extract(file SourceFile) []string {
+	manifest, err := parseToml(file.Content)
+	if err != nil {
+		return []string{}
+	}
+	if strings.HasSuffix(file.Path, "rust-toolchain.toml") {
+		return appendLabel([]string{}, rustLabel("Rust", tomlString(manifest, "toolchain", "channel")))
+	}
+
+	values := []string{}
+	for _, table := range []map[string]any{tomlMap(manifest, "package"), tomlMap(manifest, "workspace", "package")} {
+		values = appendLabel(values, rustLabel("Rust", tomlString(table, "rust-version")))
+		values = appendLabel(values, rustLabel("Rust edition", tomlString(table, "edition")))
+	}
+	for _, dependencies := range []map[string]any{tomlMap(manifest, "dependencies"), tomlMap(manifest, "workspace", "dependencies")} {
+		for _, name := range sortedKeys(dependencies) {
+			label, tracked := rustCrates[name]
+			if !tracked {
+				continue
+			}
+			version, _ := dependencies[name].(string)
+			if table, ok := dependencies[name].(map[string]any); ok {
+				version = tomlString(table, "version")
+			}
+			values = append(values, softwareLabel(label, version))
+		}
+	}
+	return values
+}
+
+// rustLabel drops labels without version.
+func rustLabel(name string, version string) string {
+	if len(version) == 0 {
+		return ""
+	}
+	return softwareLabel(name, version)
+}
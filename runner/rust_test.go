@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRustDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{"package", "Cargo.toml", "[package]\nname = \"app\"\nedition = \"2021\"\nrust-version = \"1.74\"\n\n[dependencies]\naxum = \"0.7.4\"\nserde = \"1\"\ntokio = { version = \"1.36\", features = [\"full\"] }\n", []string{"Rust 1.74", "Rust edition 2021", "Axum 0.7.4", "Tokio 1.36"}},
+		{"workspace", "Cargo.toml", "[workspace.package]\nedition = \"2021\"\n\n[workspace.dependencies]\nactix-web = \"4\"\n", []string{"Rust edition 2021", "Actix-web 4"}},
+		{"toolchain", "rust-toolchain.toml", "[toolchain]\nchannel = \"1.76.0\"\n", []string{"Rust 1.76.0"}},
+		{"toolchain without channel", "rust-toolchain.toml", "[toolchain]\ncomponents = [\"clippy\"]\n", []string{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _ := extract(t, rustDetector{}, map[string]string{tc.file: tc.content}, tc.file)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
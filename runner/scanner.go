@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Types of progress events emitted by Scanner.
+const (
+	EventFetching  = "fetching"
+	EventSkipped   = "skipped"
+	EventProcessed = "processed"
+	EventFailed    = "failed"
+	EventWarning   = "warning"
+)
+
+// Event describes progress of scan. Done and Total are set for processed and failed repositories,
+// Mode tells if processed repository was scanned, taken from cache or rescanned incrementally,
+// Skipped holds number of skipped files by reason, files under skipped directories included.
+type Event struct {
+	Type       string
+	Repository string
+	Message    string
+	Done       int
+	Total      int
+	Elapsed    time.Duration
+	Mode       string
+	Err        error
+	Skipped    map[string]int
+}
+
+// Result of scan. Failed repositories are listed in Failures and have no resources,
+// Reused is number of repositories taken from cache and Incremental of those with only changed files rescanned.
+type Result struct {
+	Resources   []Resource
+	Failures    []Failure
+	Reused      int
+	Incremental int
+}
+
+// Scanner finds references across repositories without printing anything or writing output files.
+// Cloned and downloaded repositories are kept in WorkDir, such sources fail when it is not set.
+// Findings are cached in CacheDir only when it is set. Detectors run after built-in and configured ones.
+// Progress is never called concurrently, events of different repositories are interleaved.
+type Scanner struct {
+	Config    Config
+	WorkDir   string
+	CacheDir  string
+	Progress  func(Event)
+	Detectors []Detector
+}
+
+func NewScanner(config Config) *Scanner {
+	return &Scanner{Config: config}
+}
+
+// Scan processes repositories concurrently. Error is returned for invalid config, cancelled context
+// or first failure when FailFast is set, otherwise failures are part of result.
+func (scanner *Scanner) Scan(ctx context.Context, repositories []Repository) (Result, error) {
+	executionConfig, err := executionConfig(scanner.Config, repositories)
+	if err != nil {
+		return Result{}, err
+	}
+	executionConfig.WorkDir = scanner.WorkDir
+	executionConfig.CacheDir = scanner.CacheDir
+	if scanner.Progress != nil {
+		var emitting sync.Mutex
+		executionConfig.progress = func(event Event) {
+			emitting.Lock()
+			defer emitting.Unlock()
+			scanner.Progress(event)
+		}
+	}
+	executionConfig.Detectors = append(executionConfig.Detectors, scanner.Detectors...)
+
+	if len(executionConfig.WorkDir) > 0 {
+		if err := os.MkdirAll(executionConfig.WorkDir, os.ModePerm); err != nil {
+			return Result{}, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	collector := collector{
+		executionConfig: executionConfig,
+		resources:       map[string]Resource{},
+		lock:            sync.Mutex{},
+	}
+
+	concurrency := int(executionConfig.Concurrency)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	var progress sync.Mutex
+	guard := make(chan struct{}, concurrency)
+	done := 0
+	reused := 0
+	incremental := 0
+	var firstFailure error
+
+	for _, repo := range executionConfig.Repositories {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		guard <- struct{}{}
+		go func(r Repository) {
+			defer wg.Done()
+			defer func() { <-guard }()
+
+			start := time.Now()
+			foundResource, mode, err := process(ctx, r, executionConfig)
+			elapsed := time.Since(start)
+
+			progress.Lock()
+			done = done + 1
+			event := Event{Type: EventProcessed, Repository: r.Name, Done: done, Total: len(executionConfig.Repositories), Elapsed: elapsed, Mode: mode}
+			if err != nil {
+				event.Type = EventFailed
+				event.Err = err
+				if executionConfig.FailFast && firstFailure == nil {
+					firstFailure = fmt.Errorf("repo %s failed: %w", r.Name, err)
+					cancel()
+				}
+			}
+			if err == nil && mode == ModeCached {
+				reused++
+			} else if err == nil && mode == ModeIncremental {
+				incremental++
+			}
+			executionConfig.emit(event)
+			progress.Unlock()
+
+			if err != nil {
+				collector.fail(r, err)
+				return
+			}
+			collector.merge(foundResource)
+		}(repo)
+	}
+
+	wg.Wait()
+
+	if firstFailure != nil {
+		return Result{}, firstFailure
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Resources:   ComputeUsedBy(collector.outputResourcesList()),
+		Failures:    collector.failures,
+		Reused:      reused,
+		Incremental: incremental,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScanProgressIsSerialized(t *testing.T) {
+	root := t.TempDir()
+	repositories := []Repository{}
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("repo-%d", i)
+		writeFiles(t, filepath.Join(root, name), map[string]string{
+			".gitignore": "*.log\n",
+			"app.log":    "",
+			"main.go":    "// https://api.service",
+		})
+		repositories = append(repositories, Repository{Name: name, Type: SourceLocal, Path: filepath.Join(root, name), Ref: "main"})
+	}
+
+	scanner := NewScanner(Config{
+		Patterns:     []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}},
+		Concurrency:  8,
+		DisableCache: true,
+	})
+	scanner.WorkDir = filepath.Join(root, "workdir")
+	var inflight atomic.Int32
+	events := map[string]int{}
+	scanner.Progress = func(event Event) {
+		if inflight.Add(1) > 1 {
+			t.Error("progress called concurrently")
+		}
+		events[event.Type]++
+		time.Sleep(time.Millisecond)
+		inflight.Add(-1)
+	}
+
+	if _, err := scanner.Scan(context.Background(), repositories); err != nil {
+		t.Fatal(err)
+	}
+	for _, eventType := range []string{EventWarning, EventSkipped, EventProcessed} {
+		if events[eventType] != len(repositories) {
+			t.Errorf("got %d %s events, want %d", events[eventType], eventType, len(repositories))
+		}
+	}
+}
+
+func TestScanWritesNothingByDefault(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	writeFiles(t, repo, map[string]string{"main.go": "// https://api.service"})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+	wd, _ := os.Getwd()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	scanner := NewScanner(Config{Patterns: []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}}})
+	result, err := scanner.Scan(context.Background(), []Repository{
+		{Name: "repo", Type: SourceLocal, Path: repo},
+		{Name: "clone", Type: SourceGit, Url: repo},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Resources) != 1 || len(result.Failures) != 1 || result.Failures[0].Repository != "clone" {
+		t.Errorf("got resources %+v and failures %+v, want local repo scanned and clone failed", result.Resources, result.Failures)
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 1 {
+		t.Errorf("got %d entries in working directory, want only scanned repo", len(entries))
+	}
+}
@@ -0,0 +1,296 @@
+package runner
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Stats sort keys, names sort ascending and metrics descending.
+const (
+	SortName        = "name"
+	SortFanIn       = "fan-in"
+	SortFanOut      = "fan-out"
+	SortBetweenness = "betweenness"
+	SortDepth       = "depth"
+)
+
+// Metrics of resource or group. Depth is length of longest dependency chain starting there, cycles count as single step.
+type Metrics struct {
+	FanIn       int     `json:"fanIn"`
+	FanOut      int     `json:"fanOut"`
+	Betweenness float64 `json:"betweenness"`
+	Depth       int     `json:"depth"`
+}
+
+type ResourceStats struct {
+	Tag    string `json:"tag"`
+	Group  string `json:"group,omitempty"`
+	Orphan bool   `json:"orphan"`
+	Metrics
+}
+
+// GroupStats sums betweenness of members, fan-in and fan-out count only edges crossing group boundary.
+type GroupStats struct {
+	Group     string `json:"group"`
+	Resources int    `json:"resources"`
+	Orphans   int    `json:"orphans"`
+	Metrics
+}
+
+type Stats struct {
+	Resources    []ResourceStats `json:"resources"`
+	Groups       []GroupStats    `json:"groups"`
+	LongestChain []string        `json:"longestChain"`
+	Orphans      int             `json:"orphans"`
+}
+
+// ComputeStats calculates metrics of every visible resource and of every group.
+func ComputeStats(resources []Resource, groups map[string][]string, exclude []string, validTags []string) Stats {
+	downstream := adjacency(resources, DirectionDownstream, exclude, validTags)
+	upstream := adjacency(resources, DirectionUpstream, exclude, validTags)
+
+	tags := []string{}
+	for _, resource := range resources {
+		if visible(resource.Tag, exclude, validTags) {
+			tags = append(tags, resource.Tag)
+		}
+	}
+	tags = append(tags, sortedKeys(upstream)...)
+	tags = unique(tags)
+	slices.Sort(tags)
+
+	betweenness := betweenness(tags, downstream)
+	depth, chain := longestChains(downstream)
+
+	stats := Stats{LongestChain: chain}
+	for _, tag := range tags {
+		group, _ := groupOf(tag, groups)
+		orphan := len(downstream[tag]) == 0 && len(upstream[tag]) == 0
+		if orphan {
+			stats.Orphans++
+		}
+		stats.Resources = append(stats.Resources, ResourceStats{
+			Tag:    tag,
+			Group:  group,
+			Orphan: orphan,
+			Metrics: Metrics{
+				FanIn:       len(upstream[tag]),
+				FanOut:      len(downstream[tag]),
+				Betweenness: betweenness[tag],
+				Depth:       depth[tag],
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(groups) {
+		group := GroupStats{Group: name}
+		for _, rs := range stats.Resources {
+			if !slices.Contains(groups[name], rs.Tag) {
+				continue
+			}
+			group.Resources++
+			if rs.Orphan {
+				group.Orphans++
+			}
+			group.Betweenness += rs.Betweenness
+			group.Depth = max(group.Depth, rs.Depth)
+			for _, target := range downstream[rs.Tag] {
+				if !slices.Contains(groups[name], target) {
+					group.FanOut++
+				}
+			}
+			for _, source := range upstream[rs.Tag] {
+				if !slices.Contains(groups[name], source) {
+					group.FanIn++
+				}
+			}
+		}
+		stats.Groups = append(stats.Groups, group)
+	}
+	return stats
+}
+
+// Sort orders resources and groups by key.
+func (s *Stats) Sort(key string) error {
+	metric, err := metricOf(key)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(s.Resources, func(a, b ResourceStats) int {
+		return compareMetrics(metric, a.Metrics, b.Metrics, a.Tag, b.Tag)
+	})
+	slices.SortStableFunc(s.Groups, func(a, b GroupStats) int {
+		return compareMetrics(metric, a.Metrics, b.Metrics, a.Group, b.Group)
+	})
+	return nil
+}
+
+func metricOf(key string) (func(Metrics) float64, error) {
+	switch key {
+	case SortName:
+		return nil, nil
+	case SortFanIn:
+		return func(m Metrics) float64 { return float64(m.FanIn) }, nil
+	case SortFanOut:
+		return func(m Metrics) float64 { return float64(m.FanOut) }, nil
+	case SortBetweenness:
+		return func(m Metrics) float64 { return m.Betweenness }, nil
+	case SortDepth:
+		return func(m Metrics) float64 { return float64(m.Depth) }, nil
+	}
+	return nil, fmt.Errorf("unknown sort key %s", key)
+}
+
+func compareMetrics(metric func(Metrics) float64, a, b Metrics, nameA, nameB string) int {
+	if metric != nil {
+		if c := cmp.Compare(metric(b), metric(a)); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(nameA, nameB)
+}
+
+// Markdown renders resource and group tables with summary.
+func (s Stats) Markdown() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Orphans: %d\n\n", s.Orphans))
+	sb.WriteString(fmt.Sprintf("Longest dependency chain: %s\n\n", strings.Join(s.LongestChain, " ---> ")))
+	sb.WriteString("## Resources\n\n")
+	writeMarkdownTable(&sb, s.resourceRows())
+	if len(s.Groups) > 0 {
+		sb.WriteString("\n## Groups\n\n")
+		writeMarkdownTable(&sb, s.groupRows())
+	}
+	return sb.String()
+}
+
+// CSV renders resource table, or group table when groups is set.
+func (s Stats) CSV(groups bool) string {
+	rows := s.resourceRows()
+	if groups {
+		rows = s.groupRows()
+	}
+	buf := bytes.Buffer{}
+	w := csv.NewWriter(&buf)
+	w.WriteAll(rows)
+	return buf.String()
+}
+
+func (s Stats) resourceRows() [][]string {
+	rows := [][]string{{"Resource", "Group", "Fan-in", "Fan-out", "Betweenness", "Depth", "Orphan"}}
+	for _, r := range s.Resources {
+		rows = append(rows, append([]string{r.Tag, r.Group}, append(r.Metrics.cells(), strconv.FormatBool(r.Orphan))...))
+	}
+	return rows
+}
+
+func (s Stats) groupRows() [][]string {
+	rows := [][]string{{"Group", "Resources", "Fan-in", "Fan-out", "Betweenness", "Depth", "Orphans"}}
+	for _, g := range s.Groups {
+		rows = append(rows, append([]string{g.Group, strconv.Itoa(g.Resources)}, append(g.Metrics.cells(), strconv.Itoa(g.Orphans))...))
+	}
+	return rows
+}
+
+func (m Metrics) cells() []string {
+	return []string{strconv.Itoa(m.FanIn), strconv.Itoa(m.FanOut), strconv.FormatFloat(m.Betweenness, 'f', 2, 64), strconv.Itoa(m.Depth)}
+}
+
+func writeMarkdownTable(sb *strings.Builder, rows [][]string) {
+	for i, row := range rows {
+		sb.WriteString(fmt.Sprintf("| %s |\n", strings.Join(row, " | ")))
+		if i == 0 {
+			sb.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+		}
+	}
+}
+
+// betweenness is Brandes' algorithm for directed unweighted graph.
+func betweenness(tags []string, neighbours map[string][]string) map[string]float64 {
+	centrality := map[string]float64{}
+	for _, source := range tags {
+		stack := []string{}
+		predecessors := map[string][]string{}
+		paths := map[string]float64{source: 1}
+		distance := map[string]int{source: 0}
+		queue := []string{source}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			stack = append(stack, current)
+			for _, next := range neighbours[current] {
+				if _, seen := distance[next]; !seen {
+					distance[next] = distance[current] + 1
+					queue = append(queue, next)
+				}
+				if distance[next] == distance[current]+1 {
+					paths[next] += paths[current]
+					predecessors[next] = append(predecessors[next], current)
+				}
+			}
+		}
+
+		dependency := map[string]float64{}
+		for i := len(stack) - 1; i >= 0; i-- {
+			current := stack[i]
+			for _, previous := range predecessors[current] {
+				dependency[previous] += paths[previous] / paths[current] * (1 + dependency[current])
+			}
+			if current != source {
+				centrality[current] += dependency[current]
+			}
+		}
+	}
+	return centrality
+}
+
+// longestChains returns depth of every tag and longest chain in graph condensed to strongly connected components.
+// Component is represented in chain by its first tag.
+func longestChains(neighbours map[string][]string) (map[string]int, []string) {
+	components := stronglyConnected(neighbours)
+	componentOf := map[string]int{}
+	for i, component := range components {
+		slices.Sort(component)
+		for _, tag := range component {
+			componentOf[tag] = i
+		}
+	}
+
+	// Tarjan's algorithm emits component after all reachable ones, so successors are already resolved.
+	depth := make([]int, len(components))
+	next := make([]int, len(components))
+	for i, component := range components {
+		next[i] = -1
+		for _, tag := range component {
+			for _, target := range neighbours[tag] {
+				j := componentOf[target]
+				if j != i && (depth[j]+1 > depth[i] || (depth[j]+1 == depth[i] && components[j][0] < components[next[i]][0])) {
+					depth[i] = depth[j] + 1
+					next[i] = j
+				}
+			}
+		}
+	}
+
+	depths := map[string]int{}
+	for tag, i := range componentOf {
+		depths[tag] = depth[i]
+	}
+
+	start := -1
+	for i := range components {
+		if start == -1 || depth[i] > depth[start] || (depth[i] == depth[start] && components[i][0] < components[start][0]) {
+			start = i
+		}
+	}
+	chain := []string{}
+	for i := start; i != -1; i = next[i] {
+		chain = append(chain, components[i][0])
+	}
+	return depths, chain
+}
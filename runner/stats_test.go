@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBetweenness(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		edges map[string][]string
+		want  map[string]float64
+	}{
+		{"path", map[string][]string{"a": {"b"}, "b": {"c"}}, map[string]float64{"b": 1}},
+		{"diamond", map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}}, map[string]float64{"b": 0.5, "c": 0.5}},
+		{"hub", map[string][]string{"a": {"h"}, "b": {"h"}, "h": {"c", "d"}}, map[string]float64{"h": 4}},
+		{"cycle", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}, map[string]float64{"a": 1, "b": 1, "c": 1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			neighbours := adjacency(graphOf(tc.edges), DirectionDownstream, nil, nil)
+			tags := unique(append(sortedKeys(neighbours), sortedKeys(adjacency(graphOf(tc.edges), DirectionUpstream, nil, nil))...))
+			got := map[string]float64{}
+			for tag, value := range betweenness(tags, neighbours) {
+				if value != 0 {
+					got[tag] = value
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLongestChains(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		edges     map[string][]string
+		wantDepth map[string]int
+		wantChain []string
+	}{
+		{"path", map[string][]string{"a": {"b"}, "b": {"c"}}, map[string]int{"a": 2, "b": 1, "c": 0}, []string{"a", "b", "c"}},
+		{"cycle counts once", map[string][]string{"b": {"a", "c"}, "a": {"b"}}, map[string]int{"a": 1, "b": 1, "c": 0}, []string{"a", "c"}},
+		{"tie by name", map[string][]string{"d": {"e"}, "a": {"c"}}, map[string]int{"a": 1, "c": 0, "d": 1, "e": 0}, []string{"a", "c"}},
+		{"longest branch", map[string][]string{"a": {"b", "x"}, "x": {"y"}, "y": {"z"}}, map[string]int{"a": 3, "b": 0, "x": 2, "y": 1, "z": 0}, []string{"a", "x", "y", "z"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			depth, chain := longestChains(adjacency(graphOf(tc.edges), DirectionDownstream, nil, nil))
+			if !reflect.DeepEqual(depth, tc.wantDepth) || !reflect.DeepEqual(chain, tc.wantChain) {
+				t.Errorf("got %v %v, want %v %v", depth, chain, tc.wantDepth, tc.wantChain)
+			}
+		})
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	resources := append(graphOf(map[string][]string{
+		"a": {"b", "x"},
+		"b": {"c"},
+		"d": {"c"},
+	}), Resource{Tag: "orphan"})
+	groups := map[string][]string{"front": {"a", "b"}, "back": {"c", "d"}}
+
+	stats := ComputeStats(resources, groups, []string{"x"}, nil)
+
+	wantResources := []ResourceStats{
+		{Tag: "a", Group: "front", Metrics: Metrics{FanOut: 1, Depth: 2}},
+		{Tag: "b", Group: "front", Metrics: Metrics{FanIn: 1, FanOut: 1, Betweenness: 1, Depth: 1}},
+		{Tag: "c", Group: "back", Metrics: Metrics{FanIn: 2}},
+		{Tag: "d", Group: "back", Metrics: Metrics{FanOut: 1, Depth: 1}},
+		{Tag: "orphan", Orphan: true},
+	}
+	if !reflect.DeepEqual(stats.Resources, wantResources) {
+		t.Errorf("got resources %+v, want %+v", stats.Resources, wantResources)
+	}
+	wantGroups := []GroupStats{
+		{Group: "back", Resources: 2, Metrics: Metrics{FanIn: 1, Depth: 1}},
+		{Group: "front", Resources: 2, Metrics: Metrics{FanOut: 1, Betweenness: 1, Depth: 2}},
+	}
+	if !reflect.DeepEqual(stats.Groups, wantGroups) {
+		t.Errorf("got groups %+v, want %+v", stats.Groups, wantGroups)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(stats.LongestChain, want) || stats.Orphans != 1 {
+		t.Errorf("got chain %v and %d orphans", stats.LongestChain, stats.Orphans)
+	}
+
+	for _, tc := range []struct {
+		key  string
+		want []string
+	}{
+		{SortName, []string{"a", "b", "c", "d", "orphan"}},
+		{SortFanIn, []string{"c", "b", "a", "d", "orphan"}},
+		{SortDepth, []string{"a", "b", "d", "c", "orphan"}},
+		{SortBetweenness, []string{"b", "a", "c", "d", "orphan"}},
+	} {
+		if err := stats.Sort(tc.key); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, r := range stats.Resources {
+			got = append(got, r.Tag)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("sorted by %s got %v, want %v", tc.key, got, tc.want)
+		}
+	}
+	if err := stats.Sort("size"); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+}
@@ -0,0 +1,395 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// parseToml reads TOML document into nested maps. Strings, numbers, booleans and dates are kept as strings,
+// arrays as []any, tables as map[string]any. It understands enough of TOML for build manifests, not full spec.
+func parseToml(content []byte) (map[string]any, error) {
+	p := &tomlParser{input: []rune(string(content))}
+	doc := map[string]any{}
+	current := doc
+	for {
+		p.skipBlank(true)
+		if p.done() {
+			return doc, nil
+		}
+		if p.peek() == '[' {
+			table, err := p.header(doc)
+			if err != nil {
+				return nil, err
+			}
+			current = table
+		} else if err := p.keyValue(current); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if !p.done() && p.peek() != '\n' {
+			return nil, p.errorf("expected new line")
+		}
+	}
+}
+
+type tomlParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *tomlParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tomlParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *tomlParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(p.input[p.pos:min(len(p.input), p.pos+len(prefix))]), prefix)
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	line := 1 + strings.Count(string(p.input[:min(p.pos, len(p.input))]), "\n")
+	return fmt.Errorf("toml line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipBlank skips spaces and comments, and new lines too when lines is set.
+func (p *tomlParser) skipBlank(lines bool) {
+	for !p.done() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r' || (lines && c == '\n'):
+			p.pos++
+		case c == '#':
+			for !p.done() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// header parses [table] or [[array.of.tables]] and returns table following it.
+func (p *tomlParser) header(doc map[string]any) (map[string]any, error) {
+	array := p.hasPrefix("[[")
+	p.pos++
+	if array {
+		p.pos++
+	}
+	keys, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !p.hasPrefix(closing) {
+		return nil, p.errorf("expected %s", closing)
+	}
+	p.pos += len(closing)
+
+	parent, err := tomlTable(doc, keys[:len(keys)-1])
+	if err != nil {
+		return nil, p.errorf("%s", err)
+	}
+	last := keys[len(keys)-1]
+	if !array {
+		return tomlTable(parent, []string{last})
+	}
+	tables, _ := parent[last].([]any)
+	table := map[string]any{}
+	parent[last] = append(tables, table)
+	return table, nil
+}
+
+// tomlTable walks keys from table creating missing tables, last element of array of tables is followed.
+func tomlTable(table map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			created := map[string]any{}
+			table[key] = created
+			table = created
+		case map[string]any:
+			table = next
+		case []any:
+			if len(next) == 0 {
+				return nil, fmt.Errorf("%s is not a table", key)
+			}
+			last, ok := next[len(next)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a table", key)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("%s is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+func (p *tomlParser) keyValue(table map[string]any) error {
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	if p.done() || p.peek() != '=' {
+		return p.errorf("expected =")
+	}
+	p.pos++
+	p.skipBlank(false)
+	value, err := p.value()
+	if err != nil {
+		return err
+	}
+	parent, err := tomlTable(table, keys[:len(keys)-1])
+	if err != nil {
+		return p.errorf("%s", err)
+	}
+	parent[keys[len(keys)-1]] = value
+	return nil
+}
+
+// key parses bare, quoted or dotted key.
+func (p *tomlParser) key() ([]string, error) {
+	keys := []string{}
+	for {
+		p.skipBlank(false)
+		if p.done() {
+			return nil, p.errorf("expected key")
+		}
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			key = value.(string)
+		} else {
+			start := p.pos
+			for !p.done() && isTomlBare(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected key")
+			}
+			key = string(p.input[start:p.pos])
+		}
+		keys = append(keys, key)
+		p.skipBlank(false)
+		if p.done() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isTomlBare(c rune) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *tomlParser) value() (any, error) {
+	if p.done() {
+		return nil, p.errorf("expected value")
+	}
+	switch {
+	case p.hasPrefix(`"""`):
+		return p.multilineString(`"""`, true)
+	case p.hasPrefix(`'''`):
+		return p.multilineString(`'''`, false)
+	case p.peek() == '"':
+		return p.basicString()
+	case p.peek() == '\'':
+		p.pos++
+		start := p.pos
+		for !p.done() && p.peek() != '\'' && p.peek() != '\n' {
+			p.pos++
+		}
+		if p.done() || p.peek() != '\'' {
+			return nil, p.errorf("unterminated string")
+		}
+		p.pos++
+		return string(p.input[start : p.pos-1]), nil
+	case p.peek() == '[':
+		return p.array()
+	case p.peek() == '{':
+		return p.inlineTable()
+	}
+	// Bare values end at whitespace, date and time may be separated by single space.
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(",]}#\n\r\t ", p.peek()) ||
+		p.hasPrefix(" ") && p.pos-start == len("2006-01-02") && p.pos+1 < len(p.input) && unicode.IsDigit(p.input[p.pos+1]) {
+		p.pos++
+	}
+	value := string(p.input[start:p.pos])
+	if len(value) == 0 {
+		return nil, p.errorf("expected value")
+	}
+	return value, nil
+}
+
+func (p *tomlParser) basicString() (string, error) {
+	p.pos++
+	sb := strings.Builder{}
+	for !p.done() && p.peek() != '"' && p.peek() != '\n' {
+		if p.peek() == '\\' {
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteRune(p.peek())
+		p.pos++
+	}
+	if p.done() || p.peek() != '"' {
+		return "", p.errorf("unterminated string")
+	}
+	p.pos++
+	return sb.String(), nil
+}
+
+func (p *tomlParser) multilineString(delimiter string, escapes bool) (string, error) {
+	p.pos += len(delimiter)
+	if !p.done() && p.peek() == '\n' {
+		p.pos++
+	}
+	sb := strings.Builder{}
+	for !p.done() && !p.hasPrefix(delimiter) {
+		if escapes && p.peek() == '\\' {
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteRune(p.peek())
+		p.pos++
+	}
+	if p.done() {
+		return "", p.errorf("unterminated string")
+	}
+	p.pos += len(delimiter)
+	return sb.String(), nil
+}
+
+func (p *tomlParser) escape(sb *strings.Builder) error {
+	p.pos++
+	if p.done() {
+		return p.errorf("unterminated escape")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'n':
+		sb.WriteRune('\n')
+	case 't':
+		sb.WriteRune('\t')
+	case 'r':
+		sb.WriteRune('\r')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.input) {
+			return p.errorf("invalid escape")
+		}
+		code, err := strconv.ParseUint(string(p.input[p.pos:p.pos+size]), 16, 32)
+		if err != nil {
+			return p.errorf("invalid escape")
+		}
+		sb.WriteRune(rune(code))
+		p.pos += size
+	case '\n', ' ', '\t', '\r':
+		// Line ending backslash trims following whitespace.
+		for !p.done() && strings.ContainsRune(" \t\r\n", p.peek()) {
+			p.pos++
+		}
+	default:
+		sb.WriteRune(c)
+	}
+	return nil
+}
+
+func (p *tomlParser) array() ([]any, error) {
+	p.pos++
+	values := []any{}
+	for {
+		p.skipBlank(true)
+		if p.done() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		p.skipBlank(true)
+		if p.done() || (p.peek() != ',' && p.peek() != ']') {
+			return nil, p.errorf("expected , or ]")
+		}
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (map[string]any, error) {
+	p.pos++
+	table := map[string]any{}
+	for {
+		p.skipBlank(false)
+		if p.done() {
+			return nil, p.errorf("unterminated table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+		if err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.done() || (p.peek() != ',' && p.peek() != '}') {
+			return nil, p.errorf("expected , or }")
+		}
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+// tomlString returns string found under keys or empty string.
+func tomlString(table map[string]any, keys ...string) string {
+	for i, key := range keys {
+		if i == len(keys)-1 {
+			value, _ := table[key].(string)
+			return value
+		}
+		next, ok := table[key].(map[string]any)
+		if !ok {
+			return ""
+		}
+		table = next
+	}
+	return ""
+}
+
+// tomlMap returns table found under keys or nil.
+func tomlMap(table map[string]any, keys ...string) map[string]any {
+	for _, key := range keys {
+		next, ok := table[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		table = next
+	}
+	return table
+}
@@ -0,0 +1,212 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// parseTomlSafely fails test when parser panics or does not return in time.
+func parseTomlSafely(t *testing.T, input string) (map[string]any, error) {
+	t.Helper()
+	type result struct {
+		doc   map[string]any
+		err   error
+		panic any
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{panic: r}
+			}
+		}()
+		doc, err := parseToml([]byte(input))
+		done <- result{doc: doc, err: err}
+	}()
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			t.Fatalf("parseToml(%q) panicked: %v", input, r.panic)
+		}
+		return r.doc, r.err
+	case <-time.After(time.Second):
+		t.Fatalf("parseToml(%q) did not return", input)
+		return nil, nil
+	}
+}
+
+var validToml = []struct {
+	name  string
+	input string
+	want  map[string]any
+}{
+	{
+		name:  "empty",
+		input: "",
+		want:  map[string]any{},
+	},
+	{
+		name:  "scalars and comments",
+		input: "# comment\na = \"x\" # trailing\nb = 1\nc = true\nd = 'literal \\n'\ne = 1979-05-27 07:32:00\n",
+		want:  map[string]any{"a": "x", "b": "1", "c": "true", "d": `literal \n`, "e": "1979-05-27 07:32:00"},
+	},
+	{
+		name:  "escapes",
+		input: `a = "q\"t\tn\nu\u00e9"`,
+		want:  map[string]any{"a": "q\"t\tn\nué"},
+	},
+	{
+		name:  "dotted and quoted keys",
+		input: "a.b = 1\n\"c.d\" = 2\n[e . 'f']\ng = 3\n",
+		want: map[string]any{
+			"a":   map[string]any{"b": "1"},
+			"c.d": "2",
+			"e":   map[string]any{"f": map[string]any{"g": "3"}},
+		},
+	},
+	{
+		name:  "multiline strings",
+		input: "a = \"\"\"\nmulti\\\n   line\"\"\"\nb = '''\nraw\\n'''\n",
+		want:  map[string]any{"a": "multiline", "b": `raw\n`},
+	},
+	{
+		name:  "arrays",
+		input: "a = [\n  \"x\", # comment\n  'y',\n]\nb = []\nc = [[1, 2], [3]]\n",
+		want: map[string]any{
+			"a": []any{"x", "y"},
+			"b": []any{},
+			"c": []any{[]any{"1", "2"}, []any{"3"}},
+		},
+	},
+	{
+		name:  "inline tables",
+		input: "a = { id = \"x\", version.ref = \"v\" }\nb = {}\n",
+		want: map[string]any{
+			"a": map[string]any{"id": "x", "version": map[string]any{"ref": "v"}},
+			"b": map[string]any{},
+		},
+	},
+	{
+		name:  "array of tables",
+		input: "[[bin]]\nname = \"a\"\n[[bin]]\nname = \"b\"\n[bin.extra]\nc = 1\n",
+		want: map[string]any{
+			"bin": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b", "extra": map[string]any{"c": "1"}},
+			},
+		},
+	},
+	{
+		name: "version catalog",
+		input: `[versions]
+kotlin = "1.9.20"
+spring-boot = { strictly = "3.1.5" }
+
+[plugins]
+kotlin-jvm = { id = "org.jetbrains.kotlin.jvm", version.ref = "kotlin" }
+
+[libraries]
+stdlib = "org.jetbrains.kotlin:kotlin-stdlib:1.9.20"
+`,
+		want: map[string]any{
+			"versions": map[string]any{"kotlin": "1.9.20", "spring-boot": map[string]any{"strictly": "3.1.5"}},
+			"plugins": map[string]any{
+				"kotlin-jvm": map[string]any{"id": "org.jetbrains.kotlin.jvm", "version": map[string]any{"ref": "kotlin"}},
+			},
+			"libraries": map[string]any{"stdlib": "org.jetbrains.kotlin:kotlin-stdlib:1.9.20"},
+		},
+	},
+	{
+		name: "pyproject",
+		input: `[project]
+requires-python = ">=3.11"
+dependencies = [
+  "Django==4.2.7",
+  "fastapi[all] >= 0.104, <1 ; python_version > '3.8'",
+]
+
+[tool.poetry.dependencies]
+flask = { version = "^3.0", extras = ["async"] }
+`,
+		want: map[string]any{
+			"project": map[string]any{
+				"requires-python": ">=3.11",
+				"dependencies":    []any{"Django==4.2.7", "fastapi[all] >= 0.104, <1 ; python_version > '3.8'"},
+			},
+			"tool": map[string]any{"poetry": map[string]any{"dependencies": map[string]any{
+				"flask": map[string]any{"version": "^3.0", "extras": []any{"async"}},
+			}}},
+		},
+	},
+	{
+		name:  "cargo",
+		input: "[package]\nedition = \"2021\"\n\n[dependencies]\ntokio = { version = \"1.35\", features = [\"full\"] }\n",
+		want: map[string]any{
+			"package":      map[string]any{"edition": "2021"},
+			"dependencies": map[string]any{"tokio": map[string]any{"version": "1.35", "features": []any{"full"}}},
+		},
+	},
+}
+
+func TestParseToml(t *testing.T) {
+	for _, tc := range validToml {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTomlSafely(t, tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTomlMalformed(t *testing.T) {
+	for _, input := range []string{
+		"a = []\n[a.b]\n",
+		"a = []\n[[a.b]]\n",
+		"a = [}]",
+		"a = [1 2]",
+		"a = [,]",
+		"a = [",
+		"a = [\"x\"",
+		"a = {b = 1 c = 2}",
+		"a = {",
+		"a = {b = }",
+		"a =",
+		"a = \n",
+		"= 1",
+		"a 1",
+		"a = 1 b",
+		"a = \"unterminated",
+		"a = \"line\nbreak\"",
+		"a = 'unterminated",
+		"a = \"\"\"unterminated",
+		"a = '''unterminated",
+		"a = \"\\u12\"",
+		"a = \"\\",
+		"[a",
+		"[[a]",
+		"[]",
+		"a = 1\na.b = 2\n",
+		"a = 1\n[a]\n",
+		"a = [1]\n[a]\n",
+	} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := parseTomlSafely(t, input); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+// TestParseTomlTruncated parses every prefix of valid documents, which must not panic or hang.
+func TestParseTomlTruncated(t *testing.T) {
+	for _, tc := range validToml {
+		for i := range tc.input {
+			parseTomlSafely(t, tc.input[:i])
+		}
+	}
+}
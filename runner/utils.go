@@ -0,0 +1,292 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func resolveCommit(path string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+type checkout struct {
+	repository Repository
+	path       string
+	commit     string
+}
+
+// FileFindings are references, software and base images found in single file.
+// Inputs are other files read by detectors, file is rescanned when any of them changes.
+type FileFindings struct {
+	References map[string][]Reference `json:"references,omitempty"`
+	Software   []string               `json:"software,omitempty"`
+	Images     []BaseImage            `json:"images,omitempty"`
+	Inputs     []string               `json:"inputs,omitempty"`
+}
+
+func (f FileFindings) empty() bool {
+	return len(f.References) == 0 && len(f.Software) == 0 && len(f.Images) == 0 && len(f.Inputs) == 0
+}
+
+// Findings of walked directory, Files are keyed by path relative to repository root and hold only files with findings.
+type Findings struct {
+	Files   map[string]FileFindings
+	Skipped skippedPaths
+}
+
+func findReferences(ctx context.Context, forTag string, startingPath string, source checkout, rules *ignoreRules, executionConfig ExecutionConfig) (Findings, error) {
+	files := map[string]FileFindings{}
+	skipped := newSkippedPaths()
+	err := filepath.Walk(startingPath,
+		func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if info.Name() == ".git" {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			relativePath, _ := filepath.Rel(source.path, path)
+			relativePath = filepath.ToSlash(relativePath)
+			if reason := rules.skip(path, info); len(reason) > 0 {
+				if info.IsDir() {
+					skipped.Dirs[relativePath] = skippedDir{Reason: reason, Files: countFiles(path)}
+					return filepath.SkipDir
+				}
+				skipped.Files[relativePath] = reason
+				return nil
+			}
+			if !info.IsDir() {
+				fileFindings, err := scanFile(forTag, path, relativePath, source, executionConfig)
+				if err != nil {
+					return err
+				}
+				if !fileFindings.empty() {
+					files[relativePath] = fileFindings
+				}
+			}
+
+			return nil
+		})
+
+	return Findings{
+		Files:   files,
+		Skipped: skipped,
+	}, err
+}
+
+func scanFile(forTag string, path string, relativePath string, source checkout, executionConfig ExecutionConfig) (FileFindings, error) {
+	var fxs = make([]runOnLine, 1)
+
+	refs := make(map[string][]Reference)
+	fxs[0] = func(line int, file string, content string) {
+		for _, pattern := range executionConfig.Patterns {
+			matches := pattern.Regexp.FindAllStringSubmatchIndex(content, -1)
+			group := pattern.tagGroup()
+			for _, match := range matches {
+				if match[2*group] < 0 {
+					continue
+				}
+				foundTag := resolveAlias(strings.TrimSuffix(content[match[2*group]:match[2*group+1]], pattern.TrimSuffix), executionConfig.Aliases)
+				if len(foundTag) == 0 || forTag == foundTag {
+					continue
+				}
+				refs[foundTag] = append(refs[foundTag], Reference{
+					Repository: source.repository.Name,
+					Path:       relativePath,
+					Line:       line,
+					Column:     match[0] + 1,
+					Match:      content[match[0]:match[1]],
+					Pattern:    pattern.Name,
+					Kind:       pattern.kind(),
+					Commit:     source.commit,
+				})
+			}
+		}
+	}
+	if err := referencesInFile(path, fxs); err != nil {
+		return FileFindings{}, err
+	}
+	findings, err := detectSoftware(path, relativePath, source, executionConfig)
+	if err != nil {
+		return FileFindings{}, err
+	}
+	findings.References = mergeRefs(nil, refs, executionConfig.ValidNames)
+	return findings, nil
+}
+
+// resourceFromFiles aggregates findings of files in path order, references are stamped with current commit.
+func resourceFromFiles(tag string, commit string, files map[string]FileFindings) Resource {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	references := map[string][]Reference{}
+	software := []string{}
+	images := []BaseImage{}
+	for _, path := range paths {
+		for target, refs := range files[path].References {
+			for _, ref := range refs {
+				ref.Commit = commit
+				references[target] = append(references[target], ref)
+			}
+		}
+		software = append(software, files[path].Software...)
+		images = append(images, files[path].Images...)
+	}
+	return Resource{
+		Tag:        tag,
+		Commit:     commit,
+		References: references,
+		Software:   unique(software),
+		Images:     images,
+	}
+}
+
+// changedFiles lists files changed between commit and checked out one, relative to checkout directory.
+// False is returned when diff is not available or ignore rules changed, so whole repository has to be scanned.
+func changedFiles(ctx context.Context, source checkout, from string) ([]string, bool) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", "--no-renames", "--relative", "-z", from, source.commit)
+	cmd.Dir = source.path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, false
+	}
+	changed := []string{}
+	for _, path := range strings.Split(string(out), "\x00") {
+		if len(path) == 0 {
+			continue
+		}
+		if filepath.Base(path) == ".gitignore" {
+			return nil, false
+		}
+		changed = append(changed, path)
+	}
+	return changed, true
+}
+
+type runOnLine func(int, string, string)
+
+const maxLineLength = 16 * 1024 * 1024
+
+func referencesInFile(file string, fxs []runOnLine) error {
+	readFile, err := os.Open(file)
+
+	if err != nil {
+		return err
+	}
+	defer readFile.Close()
+	fileScanner := bufio.NewScanner(readFile)
+	fileScanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineLength)
+
+	fileScanner.Split(bufio.ScanLines)
+	line := 0
+	for fileScanner.Scan() {
+		line++
+		content := fileScanner.Text()
+		for _, f := range fxs {
+			f(line, file, content)
+		}
+	}
+	if err := fileScanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+func mergeRefs(m1 map[string][]Reference, m2 map[string][]Reference, validNames []string) map[string][]Reference {
+	merged := make(map[string][]Reference)
+	for k, v := range m1 {
+		if len(validNames) == 0 || slices.Contains(validNames, k) {
+			merged[k] = v
+		}
+	}
+
+	for key, value := range m2 {
+		if len(validNames) == 0 || slices.Contains(validNames, key) {
+			merged[key] = append(merged[key], value...)
+		}
+
+	}
+
+	for key, value := range merged {
+		merged[key] = unique(value)
+	}
+
+	return merged
+}
+
+func unique[T comparable](in []T) []T {
+	var unique []T
+	m := map[T]bool{}
+
+	for _, v := range in {
+		if !m[v] {
+			m[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func readInputFile(inputFile string) ([]Repository, error) {
+	jsonFile, err := os.Open(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", inputFile, err)
+	}
+	defer jsonFile.Close()
+	data, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", inputFile, err)
+	}
+	var repos []Repository
+
+	if err := json.Unmarshal([]byte(data), &repos); err != nil {
+		return nil, fmt.Errorf("failed to parse json from file %s: %w", inputFile, err)
+	}
+	for i, repo := range repos {
+		if len(repo.Name) == 0 {
+			repos[i].Name = defaultRepoName(repo)
+		}
+	}
+	return repos, nil
+}
+
+// defaultRepoName derives name from last element of path or url, without .git and archive extensions.
+func defaultRepoName(repo Repository) string {
+	name := filepath.Base(strings.TrimSuffix(localPath(repo), "/"))
+	for _, suffix := range []string{".git", ".zip", ".tar.gz", ".tgz", ".tar"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+func resolveAlias(tag string, aliases map[string]string) string {
+	alias, ok := aliases[tag]
+	if ok {
+		return alias
+	} else {
+		return tag
+	}
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestChangedFiles(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "repo")
+	writeFiles(t, repo, map[string]string{"keep.go": "", "edit.go": "", "delete.go": "", "dir/nested.go": ""})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+	first := resolveCommit(repo)
+
+	writeFiles(t, repo, map[string]string{"edit.go": "changed", "dir/added.go": ""})
+	os.Remove(filepath.Join(repo, "delete.go"))
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-qm", "second")
+	second := resolveCommit(repo)
+
+	changed, ok := changedFiles(context.Background(), checkout{path: repo, commit: second}, first)
+	slices.Sort(changed)
+	if want := []string{"delete.go", "dir/added.go", "edit.go"}; !ok || !reflect.DeepEqual(changed, want) {
+		t.Errorf("got %v, %v, want %v", changed, ok, want)
+	}
+	if _, ok := changedFiles(context.Background(), checkout{path: repo, commit: second}, "0000000000000000000000000000000000000000"); ok {
+		t.Error("unknown commit diffed")
+	}
+
+	writeFiles(t, repo, map[string]string{"dir/.gitignore": "*.log\n"})
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-qm", "third")
+	if _, ok := changedFiles(context.Background(), checkout{path: repo, commit: resolveCommit(repo)}, second); ok {
+		t.Error("changed .gitignore does not force full scan")
+	}
+}
+
+func TestIncrementalScanMatchesFullScan(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	writeFiles(t, repo, map[string]string{
+		"main.go":               "// https://a.service",
+		"old.go":                "// https://b.service",
+		"app/build.gradle":      "plugins { id 'org.springframework.boot' version \"$springBootVersion\" }\n",
+		"app/gradle.properties": "springBootVersion=3.1.0\n",
+	})
+	git(t, repo, "init", "-q")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-qm", "first")
+
+	scan := func(cacheDir string) (string, []Resource) {
+		t.Helper()
+		config := Config{
+			Patterns:       []Pattern{{Regexp: regexp.MustCompile(`https://([a-z-]+)\.service`)}},
+			ExtendedSearch: true,
+		}
+		scanner := NewScanner(config)
+		scanner.CacheDir = cacheDir
+		mode := ""
+		scanner.Progress = func(event Event) {
+			if event.Type == EventProcessed {
+				mode = event.Mode
+			}
+		}
+		result, err := scanner.Scan(context.Background(), []Repository{{Name: "repo", Type: SourceLocal, Path: repo}})
+		if err != nil || len(result.Failures) > 0 {
+			t.Fatal(err, result.Failures)
+		}
+		return mode, result.Resources
+	}
+
+	cacheDir := filepath.Join(root, "cache")
+	scan(cacheDir)
+	writeFiles(t, repo, map[string]string{
+		"main.go":               "// https://c.service",
+		"new.go":                "// https://d.service",
+		"app/gradle.properties": "springBootVersion=3.2.0\n",
+	})
+	os.Remove(filepath.Join(repo, "old.go"))
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-qm", "second")
+
+	mode, incremental := scan(cacheDir)
+	_, full := scan("")
+	if mode != ModeIncremental {
+		t.Errorf("got mode %s, want %s", mode, ModeIncremental)
+	}
+	if !reflect.DeepEqual(incremental, full) {
+		t.Errorf("incremental %+v\ndiffers from full %+v", incremental, full)
+	}
+	if want := []string{"c", "d"}; !reflect.DeepEqual(sortedKeys(full[0].References), want) {
+		t.Errorf("got references to %v, want %v", sortedKeys(full[0].References), want)
+	}
+	if !slices.Contains(full[0].Software, "Spring 3.2.0") || slices.Contains(full[0].Software, "Spring 3.1.0") {
+		t.Errorf("build script not rescanned after its input changed: %v", full[0].Software)
+	}
+}